@@ -1,23 +1,27 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/sepuka/vkbotserver/api/button"
 	"github.com/sepuka/vkbotserver/config"
+	"github.com/sepuka/vkbotserver/errors"
 	"go.uber.org/zap"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/google/go-querystring/query"
 )
 
 const (
-	defaultOutput         = `ok`
-	Endpoint              = `https://api.vk.com/method`
-	MethodApiMessagesSend = `messages.send`
-	Version               = `5.170`
+	defaultOutput                           = `ok`
+	Endpoint                                = `https://api.vk.com/method`
+	MethodApiMessagesSend                   = `messages.send`
+	MethodApiMessagesSendMessageEventAnswer = `messages.sendMessageEventAnswer`
+	Version                                 = `5.170`
 )
 
 type (
@@ -26,9 +30,11 @@ type (
 		Message     string `url:"message"`
 		AccessToken string `url:"access_token"`
 		ApiVersion  string `url:"v"`
-		PeerId      int    `url:"peer_id"`
+		PeerId      int    `url:"peer_id,omitempty"`
+		PeerIds     string `url:"peer_ids,omitempty"`
 		RandomId    int64  `url:"random_id"`
 		Attachment  string `url:"attachment"`
+		Template    string `url:"template,omitempty"`
 	}
 
 	HTTPClient interface {
@@ -36,10 +42,12 @@ type (
 	}
 
 	Api struct {
-		logger *zap.SugaredLogger
-		cfg    config.Config
-		client HTTPClient
-		rnd    Rnder
+		logger  *zap.SugaredLogger
+		cfg     config.Config
+		client  HTTPClient
+		rnd     Rnder
+		limiter *limiter
+		retry   *retrier
 	}
 )
 
@@ -51,14 +59,27 @@ func DefaultResponseBody() []byte {
 // NewApi Creates API gate in order to communicate with VK
 func NewApi(logger *zap.SugaredLogger, cfg config.Config, client HTTPClient, rnd Rnder) *Api {
 	return &Api{
-		logger: logger,
-		cfg:    cfg,
-		client: client,
-		rnd:    rnd,
+		logger:  logger,
+		cfg:     cfg,
+		client:  client,
+		rnd:     rnd,
+		limiter: newLimiter(cfg.Api),
+		retry:   newRetrier(cfg.Api.Retry),
 	}
 }
 
-func (a *Api) SendMessage(peerId int, msg string) error {
+// LimiterStats returns how many calls the rate limiter has delayed or rejected and how long they waited
+func (a *Api) LimiterStats() LimiterStats {
+	return a.limiter.statistics()
+}
+
+// SendMessage sends text message
+func (a *Api) SendMessage(peerId int, msg string) (MessageRef, error) {
+	return a.SendMessageContext(context.Background(), peerId, msg)
+}
+
+// SendMessageContext is the context-aware variant of the SendMessage
+func (a *Api) SendMessageContext(ctx context.Context, peerId int, msg string) (MessageRef, error) {
 	var (
 		payload = OutcomeMessage{
 			Message:     msg,
@@ -69,11 +90,16 @@ func (a *Api) SendMessage(peerId int, msg string) error {
 		}
 	)
 
-	return a.send(payload)
+	return a.send(ctx, payload)
 }
 
 // SendMessageWithAttachmentAndButton Sends custom message with VK attachment
-func (a *Api) SendMessageWithAttachmentAndButton(peerId int, msg string, attachment string, keyboard button.Keyboard) error {
+func (a *Api) SendMessageWithAttachmentAndButton(peerId int, msg string, attachment string, keyboard button.Keyboard) (MessageRef, error) {
+	return a.SendMessageWithAttachmentAndButtonContext(context.Background(), peerId, msg, attachment, keyboard)
+}
+
+// SendMessageWithAttachmentAndButtonContext is the context-aware variant of the SendMessageWithAttachmentAndButton
+func (a *Api) SendMessageWithAttachmentAndButtonContext(ctx context.Context, peerId int, msg string, attachment string, keyboard button.Keyboard) (MessageRef, error) {
 	var (
 		payload = OutcomeMessage{
 			Message:     msg,
@@ -96,16 +122,21 @@ func (a *Api) SendMessageWithAttachmentAndButton(peerId int, msg string, attachm
 			).
 			Errorf(`build keyboard query string error`)
 
-		return err
+		return MessageRef{}, err
 	}
 
 	payload.Keyboard = string(js)
 
-	return a.send(payload)
+	return a.send(ctx, payload)
 }
 
 // SendMessageWithButton Sends message with keyboard
-func (a *Api) SendMessageWithButton(peerId int, msg string, keyboard button.Keyboard) error {
+func (a *Api) SendMessageWithButton(peerId int, msg string, keyboard button.Keyboard) (MessageRef, error) {
+	return a.SendMessageWithButtonContext(context.Background(), peerId, msg, keyboard)
+}
+
+// SendMessageWithButtonContext is the context-aware variant of the SendMessageWithButton
+func (a *Api) SendMessageWithButtonContext(ctx context.Context, peerId int, msg string, keyboard button.Keyboard) (MessageRef, error) {
 	var (
 		payload = OutcomeMessage{
 			Message:     msg,
@@ -127,103 +158,107 @@ func (a *Api) SendMessageWithButton(peerId int, msg string, keyboard button.Keyb
 			).
 			Errorf(`build keyboard query string error`)
 
-		return err
+		return MessageRef{}, err
 	}
 
 	payload.Keyboard = string(js)
 
-	return a.send(payload)
+	return a.send(ctx, payload)
+}
+
+// SendMessageToPeers sends the message to up to 100 peers by one call
+func (a *Api) SendMessageToPeers(peerIds []int, msg OutcomeMessage) ([]SendResult, error) {
+	return a.SendMessageToPeersContext(context.Background(), peerIds, msg)
+}
+
+// SendMessageToPeersContext is the context-aware variant of the SendMessageToPeers. The random_id of msg is kept
+// if it's set, so the caller may repeat the call without duplicates
+func (a *Api) SendMessageToPeersContext(ctx context.Context, peerIds []int, msg OutcomeMessage) ([]SendResult, error) {
+	var ids = make([]string, 0, len(peerIds))
+
+	if len(peerIds) == 0 || len(peerIds) > MaxPeerIds {
+		return nil, fmt.Errorf(`messages.send accepts 1-%d peers, %d given`, MaxPeerIds, len(peerIds))
+	}
+
+	for _, peerId := range peerIds {
+		ids = append(ids, strconv.Itoa(peerId))
+	}
+
+	if msg.RandomId == 0 {
+		msg.RandomId = a.rnd.Rnd()
+	}
+
+	msg.AccessToken, msg.ApiVersion = a.cfg.Api.Token, Version
+	msg.PeerIds, msg.PeerId = strings.Join(ids, `,`), 0
+
+	return a.sendResults(ctx, msg)
 }
 
-func (a *Api) send(msgStruct OutcomeMessage) error {
+// send sends the message by peer_ids, in this case VK returns the conversation message id
+// which group bots need to edit or delete the message
+func (a *Api) send(ctx context.Context, msgStruct OutcomeMessage) (MessageRef, error) {
 	var (
-		request      *http.Request
-		response     *http.Response
-		answer       = &Response{}
-		dumpResponse []byte
-		err          error
-		params       url.Values
-		maskedParams string
-		endpoint     string
+		results []SendResult
+		err     error
 	)
 
-	if params, err = query.Values(msgStruct); err != nil {
-		a.
-			logger.
-			With(zap.Error(err)).
-			Errorf(`build request query string error`)
+	msgStruct.PeerIds, msgStruct.PeerId = strconv.Itoa(msgStruct.PeerId), 0
 
-		return err
+	if results, err = a.sendResults(ctx, msgStruct); err != nil {
+		return MessageRef{}, err
 	}
 
-	endpoint = fmt.Sprintf(`%s/%s?%s`, Endpoint, MethodApiMessagesSend, params.Encode())
-	maskedParams = a.cfg.Api.MaskedToken(endpoint)
+	if results[0].Error != nil {
+		return MessageRef{PeerId: results[0].PeerId}, results[0].Error
+	}
 
-	if request, err = http.NewRequest(`POST`, endpoint, nil); err != nil {
-		a.
-			logger.
-			With(
-				zap.String(`request`, maskedParams),
-				zap.Error(err),
-			).
-			Errorf(`build Api request error`)
+	return results[0].MessageRef, nil
+}
 
-		return err
-	}
+func (a *Api) sendResults(ctx context.Context, msgStruct OutcomeMessage) ([]SendResult, error) {
+	var (
+		params  url.Values
+		results []SendResult
+		err     error
+	)
 
-	if response, err = a.client.Do(request); err != nil {
+	if params, err = query.Values(msgStruct); err != nil {
 		a.
 			logger.
-			With(
-				zap.String(`request`, maskedParams),
-				zap.Error(err),
-			).
-			Errorf(`send Api request error`)
+			With(zap.Error(err)).
+			Errorf(`build request query string error`)
 
-		return err
+		return nil, err
 	}
 
-	if dumpResponse, err = httputil.DumpResponse(response, true); err != nil {
-		a.
-			logger.
-			With(
-				zap.String(`request`, maskedParams),
-				zap.Error(err),
-			).
-			Errorf(`dump Api response error`)
+	if err = a.CallInto(ctx, MethodApiMessagesSend, params, &results); err != nil {
+		return nil, err
+	}
 
-		return err
+	if len(results) == 0 {
+		return nil, errors.NewInvalidJsonError(`messages.send returned no messages`, nil)
 	}
 
-	a.
-		logger.
-		With(
-			zap.String(`request`, maskedParams),
-			zap.ByteString(`response`, dumpResponse),
-		).
-		Info(`Api message sent`)
+	return results, nil
+}
 
-	if err = json.NewDecoder(response.Body).Decode(answer); err != nil {
+// request calls the Api method with params built from the struct by url tags
+func (a *Api) request(ctx context.Context, method string, msgStruct interface{}) error {
+	var (
+		params url.Values
+		err    error
+	)
+
+	if params, err = query.Values(msgStruct); err != nil {
 		a.
 			logger.
-			With(
-				zap.Error(err),
-				zap.ByteString(`response`, dumpResponse),
-			).
-			Error(`error while decoding Api response`)
+			With(zap.Error(err)).
+			Errorf(`build request query string error`)
 
 		return err
 	}
 
-	if len(answer.Error.Message) > 0 {
-		a.
-			logger.
-			With(
-				zap.Int32(`code`, answer.Error.Code),
-				zap.String(`message`, answer.Error.Message),
-			).
-			Error(`failed Api answer`)
-	}
+	_, err = a.Call(ctx, method, params)
 
-	return nil
+	return err
 }
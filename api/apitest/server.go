@@ -0,0 +1,64 @@
+package apitest
+
+import (
+	"github.com/sepuka/vkbotserver/api/mocks"
+	"github.com/stretchr/testify/mock"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Server is the api.HTTPClient answering VK API calls by the answer func, it records the form of every call
+type Server struct {
+	mocks.HTTPClient
+	mu     sync.Mutex
+	answer func(form url.Values) string
+	forms  []url.Values
+}
+
+// NewServer creates the client, answer gets the form of the call and returns the response body
+func NewServer(answer func(form url.Values) string) *Server {
+	var server = &Server{answer: answer}
+
+	server.On(`Do`, mock.Anything).Return(server.do, nil)
+
+	return server
+}
+
+// NewQueueServer creates the client answering by the queued bodies, then by the fallback one
+func NewQueueServer(fallback string, bodies ...string) *Server {
+	return NewServer(func(url.Values) string {
+		var body = fallback
+
+		if len(bodies) > 0 {
+			body, bodies = bodies[0], bodies[1:]
+		}
+
+		return body
+	})
+}
+
+// Forms returns forms of the calls in the order they were made
+func (s *Server) Forms() []url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]url.Values(nil), s.forms...)
+}
+
+func (s *Server) do(req *http.Request) *http.Response {
+	var (
+		body, _ = req.GetBody()
+		data, _ = ioutil.ReadAll(body)
+		form, _ = url.ParseQuery(string(data))
+	)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.forms = append(s.forms, form)
+
+	return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(s.answer(form)))}
+}
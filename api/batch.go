@@ -0,0 +1,230 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/mailru/easyjson"
+	"go.uber.org/zap"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	MethodApiExecute = `execute`
+	// MaxExecuteCalls is the VK limit of API calls made by one execute
+	MaxExecuteCalls = 25
+)
+
+var methodRegexp = regexp.MustCompile(`^[a-zA-Z]+\.[a-zA-Z]+$`)
+
+type (
+	batchResult struct {
+		response json.RawMessage
+		err      error
+	}
+
+	batchCall struct {
+		method string
+		params url.Values
+		result chan batchResult
+	}
+
+	// Batcher collects API calls made within the window and sends them by one execute request.
+	// Each caller gets its own result or its own error from execute_errors
+	Batcher struct {
+		api     *Api
+		window  time.Duration
+		mu      sync.Mutex
+		pending []*batchCall
+		timer   *time.Timer
+		running sync.WaitGroup
+		ctx     context.Context
+		cancel  context.CancelFunc
+	}
+)
+
+// NewBatcher creates batcher which sends collected calls when there're 25 of them or the window is passed since the first one
+func NewBatcher(api *Api, window time.Duration) *Batcher {
+	var ctx, cancel = context.WithCancel(context.Background())
+
+	return &Batcher{
+		api:    api,
+		window: window,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Call queues the call and waits for its result. Calls with their own access_token are made directly.
+// The call may be sent even if ctx is done while waiting for the batch
+func (b *Batcher) Call(ctx context.Context, method string, params url.Values) (json.RawMessage, error) {
+	var call = &batchCall{
+		method: method,
+		params: params,
+		result: make(chan batchResult, 1),
+	}
+
+	if params.Get(paramAccessToken) != `` {
+		return b.api.Call(ctx, method, params)
+	}
+
+	if !methodRegexp.MatchString(method) {
+		return nil, fmt.Errorf(`method "%s" can't be called by execute`, method)
+	}
+
+	b.enqueue(call)
+
+	select {
+	case result := <-call.result:
+		return result.response, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// CallInto queues the call and decodes its result into out
+func (b *Batcher) CallInto(ctx context.Context, method string, params url.Values, out interface{}) error {
+	var (
+		response json.RawMessage
+		err      error
+	)
+
+	if response, err = b.Call(ctx, method, params); err != nil {
+		return err
+	}
+
+	if unmarshaler, ok := out.(easyjson.Unmarshaler); ok {
+		return easyjson.Unmarshal(response, unmarshaler)
+	}
+
+	return json.Unmarshal(response, out)
+}
+
+// Flush sends queued calls without waiting for the window
+func (b *Batcher) Flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.flush()
+}
+
+// Close sends queued calls and waits for all sent batches
+func (b *Batcher) Close() {
+	b.Flush()
+	b.running.Wait()
+	b.cancel()
+}
+
+func (b *Batcher) enqueue(call *batchCall) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pending = append(b.pending, call)
+
+	if len(b.pending) >= MaxExecuteCalls {
+		b.flush()
+		return
+	}
+
+	if len(b.pending) == 1 {
+		b.timer = time.AfterFunc(b.window, b.Flush)
+	}
+}
+
+// flush must be called under the lock
+func (b *Batcher) flush() {
+	var batch = b.pending
+
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	if len(batch) == 0 {
+		return
+	}
+
+	b.pending = nil
+	b.running.Add(1)
+
+	go func() {
+		defer b.running.Done()
+		b.execute(batch)
+	}()
+}
+
+func (b *Batcher) execute(batch []*batchCall) {
+	var (
+		code    = compile(batch)
+		answer  *envelope
+		results []json.RawMessage
+		err     error
+	)
+
+	if answer, err = b.api.invoke(b.ctx, MethodApiExecute, url.Values{`code`: {code}}); err == nil {
+		err = json.Unmarshal(answer.Response, &results)
+	}
+
+	if err == nil && len(results) != len(batch) {
+		err = fmt.Errorf(`execute returned %d results for %d calls`, len(results), len(batch))
+	}
+
+	if err != nil {
+		b.
+			api.
+			logger.
+			With(
+				zap.Int(`calls`, len(batch)),
+				zap.Error(err),
+			).
+			Error(`execute batch error`)
+
+		for _, call := range batch {
+			call.result <- batchResult{err: err}
+		}
+
+		return
+	}
+
+	demultiplex(batch, results, answer.ExecuteErrors)
+}
+
+// demultiplex passes results to callers, VK returns false for failed calls and lists their errors in order of the calls
+func demultiplex(batch []*batchCall, results []json.RawMessage, executeErrors []Error) {
+	for i, call := range batch {
+		if string(results[i]) != `false` || len(executeErrors) == 0 {
+			call.result <- batchResult{response: results[i]}
+			continue
+		}
+
+		var failure = executeErrors[0]
+
+		executeErrors = executeErrors[1:]
+		call.result <- batchResult{err: &failure}
+	}
+}
+
+// compile builds VKScript returning results of all calls like
+// return [API.messages.send({"peer_id":"1","message":"hi"}),API.users.get({"user_ids":"1"})];
+func compile(batch []*batchCall) string {
+	var calls = make([]string, 0, len(batch))
+
+	for _, call := range batch {
+		var args = make(map[string]string, len(call.params))
+
+		for key, values := range call.params {
+			if key != paramVersion {
+				args[key] = strings.Join(values, `,`)
+			}
+		}
+
+		js, _ := json.Marshal(args)
+		calls = append(calls, `API.`+call.method+`(`+string(js)+`)`)
+	}
+
+	return `return [` + strings.Join(calls, `,`) + `];`
+}
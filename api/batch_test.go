@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/sepuka/vkbotserver/api/mocks"
+	"github.com/sepuka/vkbotserver/config"
+	"github.com/sepuka/vkbotserver/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatcher_Call(t *testing.T) {
+	const executeAnswer = `{"response":[100,false,[{"id":1,"first_name":"Pavel"}]],"execute_errors":[{"method":"messages.send","error_code":901,"error_msg":"Can't send messages for users without permission"}]}`
+
+	var (
+		client  = &mocks.HTTPClient{}
+		vk      = NewApi(zap.NewNop().Sugar(), config.Config{Api: config.Api{Token: `group_token`}}, client, &mocks.Rnder{})
+		batcher = NewBatcher(vk, time.Hour)
+		code    string
+		calls   = []struct {
+			method string
+			params url.Values
+		}{
+			{method: `messages.send`, params: url.Values{`peer_id`: {`1`}, `message`: {`hi`}}},
+			{method: `messages.send`, params: url.Values{`peer_id`: {`2`}, `message`: {`hi`}}},
+			{method: `users.get`, params: url.Values{`user_ids`: {`1`}, `v`: {`5.131`}}},
+		}
+		responses = make([]json.RawMessage, len(calls))
+		errs      = make([]error, len(calls))
+		wg        sync.WaitGroup
+	)
+
+	client.
+		On(`Do`, mock.MatchedBy(func(req *http.Request) bool {
+			code = requestForm(req).Get(`code`)
+			return req.URL.Path == `/method/execute`
+		})).
+		Once().
+		Return(answer(http.StatusOK, executeAnswer), nil)
+
+	for i, call := range calls {
+		wg.Add(1)
+		go func(i int, method string, params url.Values) {
+			defer wg.Done()
+			responses[i], errs[i] = batcher.Call(context.Background(), method, params)
+		}(i, call.method, call.params)
+
+		assert.Eventually(t, func() bool {
+			batcher.mu.Lock()
+			defer batcher.mu.Unlock()
+
+			return len(batcher.pending) == i+1
+		}, time.Second, time.Millisecond)
+	}
+
+	batcher.Close()
+	wg.Wait()
+
+	assert.Equal(t, `return [API.messages.send({"message":"hi","peer_id":"1"}),API.messages.send({"message":"hi","peer_id":"2"}),API.users.get({"user_ids":"1"})];`, code)
+	assert.Nil(t, errs[0])
+	assert.Equal(t, `100`, string(responses[0]))
+	assert.ErrorIs(t, errs[1], errors.ApiCantSendToUser)
+	assert.Nil(t, errs[2])
+	assert.JSONEq(t, `[{"id":1,"first_name":"Pavel"}]`, string(responses[2]))
+	client.AssertExpectations(t)
+}
+
+func TestBatcher_CallFlushesFullBatch(t *testing.T) {
+	var (
+		client   = &mocks.HTTPClient{}
+		vk       = NewApi(zap.NewNop().Sugar(), config.Config{}, client, &mocks.Rnder{})
+		batcher  = NewBatcher(vk, time.Hour)
+		response = `{"response":[1,1,1,1,1,1,1,1,1,1,1,1,1,1,1,1,1,1,1,1,1,1,1,1,1]}`
+		wg       sync.WaitGroup
+	)
+
+	client.On(`Do`, mock.Anything).Once().Return(answer(http.StatusOK, response), nil)
+
+	for i := 0; i < MaxExecuteCalls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var result, err = batcher.Call(context.Background(), `messages.markAsRead`, url.Values{`peer_id`: {`1`}})
+
+			assert.Nil(t, err)
+			assert.Equal(t, `1`, string(result))
+		}()
+	}
+
+	wg.Wait()
+	client.AssertExpectations(t)
+}
+
+func TestBatcher_CallError(t *testing.T) {
+	var (
+		client  = &mocks.HTTPClient{}
+		vk      = NewApi(zap.NewNop().Sugar(), config.Config{}, client, &mocks.Rnder{})
+		batcher = NewBatcher(vk, time.Millisecond)
+	)
+
+	client.On(`Do`, mock.Anything).Once().Return(answer(http.StatusOK, `{"error":{"error_code":5,"error_msg":"User authorization failed"}}`), nil)
+
+	_, err := batcher.Call(context.Background(), `messages.send`, url.Values{`peer_id`: {`1`}})
+	assert.ErrorIs(t, err, errors.ApiAuthFailed)
+
+	_, err = batcher.Call(context.Background(), `messages.send({});API.account.ban`, nil)
+	assert.NotNil(t, err, `method name can't inject VKScript`)
+	client.AssertNumberOfCalls(t, `Do`, 1)
+}
@@ -0,0 +1,51 @@
+package button
+
+import "github.com/sepuka/vkbotserver/domain"
+
+// Adapt downgrades the keyboard to features supported by the user's client:
+// inline keyboard becomes a regular one, callback buttons become text ones and buttons of other unsupported types are dropped.
+// Keyboard is returned as is if the client info is unknown and without buttons if the client has no keyboard at all
+func (k Keyboard) Adapt(info domain.ClientInfo) Keyboard {
+	var (
+		adapted = Keyboard{
+			OneTime: k.OneTime,
+			Inline:  k.Inline,
+			Buttons: [][]Button{},
+		}
+		row []Button
+	)
+
+	if !info.IsKnown() {
+		return k
+	}
+
+	if adapted.Inline && !info.InlineKeyboard {
+		adapted.Inline = false
+	}
+
+	if !adapted.Inline && !info.Keyboard {
+		return adapted
+	}
+
+	for _, buttons := range k.Buttons {
+		row = make([]Button, 0, len(buttons))
+		for _, btn := range buttons {
+			// any client having a keyboard supports text buttons
+			if btn.Action.Type == TextButton || info.SupportsAction(string(btn.Action.Type)) {
+				row = append(row, btn)
+				continue
+			}
+
+			if btn.Action.Type == CallbackButton {
+				btn.Action.Type = TextButton
+				row = append(row, btn)
+			}
+		}
+
+		if len(row) > 0 {
+			adapted.Buttons = append(adapted.Buttons, row)
+		}
+	}
+
+	return adapted
+}
@@ -0,0 +1,65 @@
+package button
+
+import (
+	"github.com/sepuka/vkbotserver/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestKeyboard_Adapt(t *testing.T) {
+	var (
+		text     = Button{Action: Action{Type: TextButton, Label: `text`}, Color: PrimaryColor}
+		callback = Button{Action: Action{Type: CallbackButton, Label: `callback`, Payload: `{"command":"start"}`}}
+		link     = Button{Action: Action{Type: OpenLinkButton, Label: `link`}}
+		location = Button{Action: Action{Type: LocationButton}}
+		keyboard = Keyboard{
+			Inline: true,
+			Buttons: [][]Button{
+				{text, callback},
+				{link},
+				{location},
+			},
+		}
+		downgradedCallback = Button{Action: Action{Type: TextButton, Label: `callback`, Payload: `{"command":"start"}`}}
+
+		tests = map[string]struct {
+			info     domain.ClientInfo
+			expected Keyboard
+		}{
+			`unknown client`: {
+				info:     domain.ClientInfo{},
+				expected: keyboard,
+			},
+			`full support`: {
+				info: domain.ClientInfo{
+					ButtonActions:  []string{`text`, `vkpay`, `open_app`, `location`, `open_link`, `callback`},
+					Keyboard:       true,
+					InlineKeyboard: true,
+				},
+				expected: keyboard,
+			},
+			`no callback and inline`: {
+				info: domain.ClientInfo{
+					ButtonActions: []string{`text`, `location`},
+					Keyboard:      true,
+				},
+				expected: Keyboard{
+					Buttons: [][]Button{
+						{text, downgradedCallback},
+						{location},
+					},
+				},
+			},
+			`no keyboard`: {
+				info: domain.ClientInfo{
+					ButtonActions: []string{`text`},
+				},
+				expected: Keyboard{Buttons: [][]Button{}},
+			},
+		}
+	)
+
+	for testName, testCase := range tests {
+		assert.Equal(t, testCase.expected, keyboard.Adapt(testCase.info), testName)
+	}
+}
@@ -0,0 +1,238 @@
+package button
+
+import (
+	"fmt"
+	"github.com/sepuka/vkbotserver/errors"
+	"unicode/utf8"
+)
+
+// VK limits, see https://dev.vk.com/api/bots/development/keyboard
+const (
+	MaxButtonsInRow  = 5
+	MaxRows          = 10
+	MaxButtons       = 40
+	MaxInlineRows    = 6
+	MaxInlineButtons = 10
+	MaxLabelLength   = 40
+	MaxPayloadLength = 255
+)
+
+// KeyboardBuilder builds keyboards button by button, Build validates the result against VK limits
+type KeyboardBuilder struct {
+	keyboard Keyboard
+	err      error
+}
+
+// NewKeyboard starts the regular keyboard with the first row
+func NewKeyboard() *KeyboardBuilder {
+	return &KeyboardBuilder{
+		keyboard: Keyboard{
+			Buttons: [][]Button{{}},
+		},
+	}
+}
+
+// Inline makes the keyboard shown inside the message
+func (b *KeyboardBuilder) Inline() *KeyboardBuilder {
+	b.keyboard.Inline = true
+
+	return b
+}
+
+// OneTime makes the keyboard hidden after the first button pressing
+func (b *KeyboardBuilder) OneTime() *KeyboardBuilder {
+	b.keyboard.OneTime = true
+
+	return b
+}
+
+// Row starts the next row of buttons
+func (b *KeyboardBuilder) Row() *KeyboardBuilder {
+	if len(b.keyboard.Buttons[len(b.keyboard.Buttons)-1]) > 0 {
+		b.keyboard.Buttons = append(b.keyboard.Buttons, []Button{})
+	}
+
+	return b
+}
+
+// Text adds the button which sends the label as a message, the payload is optional
+func (b *KeyboardBuilder) Text(label string, payload *Payload) *KeyboardBuilder {
+	return b.add(Action{
+		Type:    TextButton,
+		Label:   Text(label),
+		Payload: b.payload(payload),
+	})
+}
+
+// Callback adds the button which sends message_event instead of a message
+func (b *KeyboardBuilder) Callback(label string, payload *Payload) *KeyboardBuilder {
+	return b.add(Action{
+		Type:    CallbackButton,
+		Label:   Text(label),
+		Payload: b.payload(payload),
+	})
+}
+
+// OpenLink adds the button which opens the link
+func (b *KeyboardBuilder) OpenLink(label string, link string) *KeyboardBuilder {
+	return b.add(Action{
+		Type:  OpenLinkButton,
+		Label: Text(label),
+		Link:  link,
+	})
+}
+
+// Location adds the button which sends the user's location
+func (b *KeyboardBuilder) Location() *KeyboardBuilder {
+	return b.add(Action{
+		Type: LocationButton,
+	})
+}
+
+// VKPay adds the VK Pay button, the hash describes the payment like "action=transfer-to-group&group_id=1"
+func (b *KeyboardBuilder) VKPay(hash string) *KeyboardBuilder {
+	return b.add(Action{
+		Type: VkPayButton,
+		Hash: hash,
+	})
+}
+
+// OpenApp adds the button which opens the VK Mini App, ownerId and hash are optional
+func (b *KeyboardBuilder) OpenApp(label string, appId int, ownerId int, hash string) *KeyboardBuilder {
+	return b.add(Action{
+		Type:    OpenAppButton,
+		Label:   Text(label),
+		AppId:   appId,
+		OwnerId: ownerId,
+		Hash:    hash,
+	})
+}
+
+// Color colors the last added button, only text and callback buttons may be colored
+func (b *KeyboardBuilder) Color(color string) *KeyboardBuilder {
+	var row = b.keyboard.Buttons[len(b.keyboard.Buttons)-1]
+
+	if len(row) == 0 {
+		b.fail(`color is set before any button of the row`)
+		return b
+	}
+
+	if btn := &row[len(row)-1]; btn.Action.Type == TextButton || btn.Action.Type == CallbackButton {
+		btn.Color = color
+	} else {
+		b.fail(fmt.Sprintf(`%s button can't be colored`, btn.Action.Type))
+	}
+
+	return b
+}
+
+// Build returns the keyboard or the InvalidKeyboard error describing the first violated limit
+func (b *KeyboardBuilder) Build() (Keyboard, error) {
+	var (
+		keyboard = b.keyboard
+		maxRows  = MaxRows
+		maxTotal = MaxButtons
+		total    int
+	)
+
+	if b.err != nil {
+		return Keyboard{}, b.err
+	}
+
+	if last := len(keyboard.Buttons) - 1; len(keyboard.Buttons[last]) == 0 {
+		keyboard.Buttons = keyboard.Buttons[:last]
+	}
+
+	if keyboard.Inline {
+		maxRows, maxTotal = MaxInlineRows, MaxInlineButtons
+	}
+
+	if len(keyboard.Buttons) > maxRows {
+		return Keyboard{}, errors.NewInvalidKeyboardError(fmt.Sprintf(`keyboard has %d rows, max is %d`, len(keyboard.Buttons), maxRows))
+	}
+
+	for i, row := range keyboard.Buttons {
+		if len(row) > MaxButtonsInRow {
+			return Keyboard{}, errors.NewInvalidKeyboardError(fmt.Sprintf(`row %d has %d buttons, max is %d`, i+1, len(row), MaxButtonsInRow))
+		}
+
+		for _, btn := range row {
+			if err := b.validate(btn, len(row)); err != nil {
+				return Keyboard{}, errors.NewInvalidKeyboardError(fmt.Sprintf(`row %d: %s`, i+1, err))
+			}
+		}
+
+		total += len(row)
+	}
+
+	if total > maxTotal {
+		return Keyboard{}, errors.NewInvalidKeyboardError(fmt.Sprintf(`keyboard has %d buttons, max is %d`, total, maxTotal))
+	}
+
+	return keyboard, nil
+}
+
+func (b *KeyboardBuilder) validate(btn Button, rowLength int) error {
+	var action = btn.Action
+
+	if length := utf8.RuneCountInString(string(action.Label)); length > MaxLabelLength {
+		return fmt.Errorf(`label "%s" has %d chars, max is %d`, action.Label, length, MaxLabelLength)
+	}
+
+	if len(action.Payload) > MaxPayloadLength {
+		return fmt.Errorf(`payload of "%s" has %d bytes, max is %d`, action.Label, len(action.Payload), MaxPayloadLength)
+	}
+
+	switch action.Type {
+	case TextButton, CallbackButton, OpenLinkButton, OpenAppButton:
+		if action.Label == `` {
+			return fmt.Errorf(`%s button must have a label`, action.Type)
+		}
+	}
+
+	switch action.Type {
+	case OpenLinkButton:
+		if action.Link == `` {
+			return fmt.Errorf(`open_link button "%s" must have a link`, action.Label)
+		}
+	case OpenAppButton:
+		if action.AppId == 0 {
+			return fmt.Errorf(`open_app button "%s" must have an app id`, action.Label)
+		}
+	case VkPayButton:
+		if action.Hash == `` {
+			return fmt.Errorf(`vkpay button must have a hash`)
+		}
+	}
+
+	switch action.Type {
+	case LocationButton, VkPayButton, OpenAppButton:
+		if rowLength > 1 {
+			return fmt.Errorf(`%s button must be alone in the row`, action.Type)
+		}
+	}
+
+	return nil
+}
+
+func (b *KeyboardBuilder) add(action Action) *KeyboardBuilder {
+	var last = len(b.keyboard.Buttons) - 1
+
+	b.keyboard.Buttons[last] = append(b.keyboard.Buttons[last], Button{Action: action})
+
+	return b
+}
+
+func (b *KeyboardBuilder) payload(payload *Payload) string {
+	if payload == nil {
+		return ``
+	}
+
+	return payload.String()
+}
+
+func (b *KeyboardBuilder) fail(msg string) {
+	if b.err == nil {
+		b.err = errors.NewInvalidKeyboardError(msg)
+	}
+}
@@ -0,0 +1,107 @@
+package button
+
+import (
+	"encoding/json"
+	"github.com/sepuka/vkbotserver/errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestKeyboardBuilder_Build(t *testing.T) {
+	var keyboard, err = NewKeyboard().
+		OneTime().
+		Text(`Start`, &Payload{Command: `start`}).Color(PrimaryColor).
+		Callback(`Like`, &Payload{Command: `like`}).
+		Row().
+		OpenLink(`Site`, `https://vk.com`).
+		Row().
+		Location().
+		Row().
+		VKPay(`action=transfer-to-group&group_id=1`).
+		Row().
+		OpenApp(`Game`, 6232540, -1, `start`).
+		Row().
+		Build()
+
+	assert.Nil(t, err)
+
+	js, err := json.Marshal(keyboard)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"one_time":true,"inline":false,"buttons":[`+
+		`[{"action":{"type":"text","label":"Start","payload":"{\"command\":\"start\",\"id\":\"\"}"},"color":"primary"},`+
+		`{"action":{"type":"callback","label":"Like","payload":"{\"command\":\"like\",\"id\":\"\"}"}}],`+
+		`[{"action":{"type":"open_link","label":"Site","link":"https://vk.com"}}],`+
+		`[{"action":{"type":"location"}}],`+
+		`[{"action":{"type":"vkpay","hash":"action=transfer-to-group&group_id=1"}}],`+
+		`[{"action":{"type":"open_app","label":"Game","app_id":6232540,"owner_id":-1,"hash":"start"}}]]}`, string(js))
+}
+
+func TestKeyboardBuilder_BuildLimits(t *testing.T) {
+	var (
+		buttons = func(builder *KeyboardBuilder, rows int, inRow int) *KeyboardBuilder {
+			for i := 0; i < rows; i++ {
+				builder.Row()
+				for j := 0; j < inRow; j++ {
+					builder.Text(`button`, nil)
+				}
+			}
+
+			return builder
+		}
+		tests = map[string]struct {
+			builder *KeyboardBuilder
+			valid   bool
+		}{
+			`regular max`: {
+				builder: buttons(NewKeyboard(), 8, 5),
+				valid:   true,
+			},
+			`regular too many rows`: {
+				builder: buttons(NewKeyboard(), 11, 1),
+			},
+			`regular too many buttons`: {
+				builder: buttons(NewKeyboard(), 9, 5),
+			},
+			`too many buttons in row`: {
+				builder: buttons(NewKeyboard(), 1, 6),
+			},
+			`inline max`: {
+				builder: buttons(NewKeyboard().Inline(), 2, 5),
+				valid:   true,
+			},
+			`inline too many rows`: {
+				builder: buttons(NewKeyboard().Inline(), 7, 1),
+			},
+			`inline too many buttons`: {
+				builder: buttons(NewKeyboard().Inline(), 3, 4),
+			},
+			`long label`: {
+				builder: NewKeyboard().Text(strings.Repeat(`я`, 41), nil),
+			},
+			`label of max length`: {
+				builder: NewKeyboard().Text(strings.Repeat(`я`, 40), nil),
+				valid:   true,
+			},
+			`location is not alone`: {
+				builder: NewKeyboard().Location().Text(`button`, nil),
+			},
+			`link without url`: {
+				builder: NewKeyboard().OpenLink(`Site`, ``),
+			},
+			`colored link`: {
+				builder: NewKeyboard().OpenLink(`Site`, `https://vk.com`).Color(PositiveColor),
+			},
+		}
+	)
+
+	for testName, testCase := range tests {
+		var _, err = testCase.builder.Build()
+
+		if testCase.valid {
+			assert.Nil(t, err, testName)
+		} else {
+			assert.ErrorIs(t, err, errors.InvalidKeyboard, testName)
+		}
+	}
+}
@@ -7,6 +7,13 @@ const (
 	SecondaryColor = `secondary`
 	NegativeColor  = `negative`
 	PositiveColor  = `positive`
+
+	TextButton     Type = `text`
+	CallbackButton Type = `callback`
+	OpenLinkButton Type = `open_link`
+	LocationButton Type = `location`
+	VkPayButton    Type = `vkpay`
+	OpenAppButton  Type = `open_app`
 )
 
 // see full docs https://vk.com/dev/bots_docs_3
@@ -15,21 +22,27 @@ type (
 	Text string
 	// type belongs to the set of values: text, callback, open_link, location, vkpay, open_app
 	Type string
-	// there're action of button & args
+	// there're action of button & args, the set of args depends on the type
 	Action struct {
 		Type    Type   `json:"type"`
-		Label   Text   `json:"label"`
-		Payload string `json:"payload"`
+		Label   Text   `json:"label,omitempty"`
+		Payload string `json:"payload,omitempty"`
+		Link    string `json:"link,omitempty"`
+		AppId   int    `json:"app_id,omitempty"`
+		OwnerId int    `json:"owner_id,omitempty"`
+		Hash    string `json:"hash,omitempty"`
 	}
 
-	// Color belongs to the set: primary, secondary, negative, positive
+	// Color belongs to the set: primary, secondary, negative, positive. It's used by text and callback buttons only
 	Button struct {
 		Action Action `json:"action"`
-		Color  string `json:"color"`
+		Color  string `json:"color,omitempty"`
 	}
 
+	// Inline keyboard is shown inside the message
 	Keyboard struct {
 		OneTime bool       `json:"one_time"`
+		Inline  bool       `json:"inline"`
 		Buttons [][]Button `json:"buttons"`
 	}
 
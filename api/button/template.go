@@ -0,0 +1,117 @@
+package button
+
+import (
+	"fmt"
+	"github.com/sepuka/vkbotserver/errors"
+	"unicode/utf8"
+)
+
+const (
+	CarouselTemplate = `carousel`
+
+	OpenLinkElementAction  = `open_link`
+	OpenPhotoElementAction = `open_photo`
+
+	MaxCarouselElements   = 10
+	MaxElementButtons     = 3
+	MaxElementTitleLength = 80
+)
+
+type (
+	// ElementAction is done by clicking the element, type is open_link or open_photo
+	ElementAction struct {
+		Type string `json:"type"`
+		Link string `json:"link,omitempty"`
+	}
+
+	// Element is the carousel card, photo_id looks like "-109837093_457242809"
+	Element struct {
+		Title       string         `json:"title,omitempty"`
+		Description string         `json:"description,omitempty"`
+		PhotoId     string         `json:"photo_id,omitempty"`
+		Action      *ElementAction `json:"action,omitempty"`
+		Buttons     []Button       `json:"buttons"`
+	}
+
+	// Template is the message template, see https://dev.vk.com/api/bots/development/messages#carousels
+	Template struct {
+		Type     string    `json:"type"`
+		Elements []Element `json:"elements"`
+	}
+)
+
+// NewCarousel creates the carousel template
+func NewCarousel(elements ...Element) Template {
+	return Template{
+		Type:     CarouselTemplate,
+		Elements: elements,
+	}
+}
+
+// Validate returns the InvalidTemplate error if VK would reject the template.
+// Carousel has 1-10 elements, all of them have the same set of fields and the same number of buttons from 1 to 3
+func (t Template) Validate() error {
+	var first Element
+
+	if t.Type != CarouselTemplate {
+		return errors.NewInvalidTemplateError(fmt.Sprintf(`unknown template type "%s"`, t.Type))
+	}
+
+	if len(t.Elements) == 0 || len(t.Elements) > MaxCarouselElements {
+		return errors.NewInvalidTemplateError(fmt.Sprintf(`carousel has %d elements, must be 1-%d`, len(t.Elements), MaxCarouselElements))
+	}
+
+	first = t.Elements[0]
+
+	for i, element := range t.Elements {
+		if err := element.validate(); err != nil {
+			return errors.NewInvalidTemplateError(fmt.Sprintf(`element %d: %s`, i+1, err))
+		}
+
+		if len(element.Buttons) != len(first.Buttons) {
+			return errors.NewInvalidTemplateError(fmt.Sprintf(`element %d has %d buttons, the first one has %d`, i+1, len(element.Buttons), len(first.Buttons)))
+		}
+
+		if (element.PhotoId == ``) != (first.PhotoId == ``) || (element.Title == ``) != (first.Title == ``) {
+			return errors.NewInvalidTemplateError(fmt.Sprintf(`element %d has other fields than the first one`, i+1))
+		}
+	}
+
+	return nil
+}
+
+func (e Element) validate() error {
+	if e.Title == `` && e.PhotoId == `` {
+		return fmt.Errorf(`element must have a title or a photo`)
+	}
+
+	if e.Title != `` && e.Description == `` {
+		return fmt.Errorf(`element with a title must have a description`)
+	}
+
+	if length := utf8.RuneCountInString(e.Title); length > MaxElementTitleLength {
+		return fmt.Errorf(`title has %d chars, max is %d`, length, MaxElementTitleLength)
+	}
+
+	if length := utf8.RuneCountInString(e.Description); length > MaxElementTitleLength {
+		return fmt.Errorf(`description has %d chars, max is %d`, length, MaxElementTitleLength)
+	}
+
+	if len(e.Buttons) == 0 || len(e.Buttons) > MaxElementButtons {
+		return fmt.Errorf(`element has %d buttons, must be 1-%d`, len(e.Buttons), MaxElementButtons)
+	}
+
+	if e.Action != nil {
+		switch e.Action.Type {
+		case OpenLinkElementAction:
+			if e.Action.Link == `` {
+				return fmt.Errorf(`open_link action must have a link`)
+			}
+		case OpenPhotoElementAction:
+		default:
+			return fmt.Errorf(`unknown action type "%s"`, e.Action.Type)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package button
+
+import (
+	"github.com/sepuka/vkbotserver/errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestTemplate_Validate(t *testing.T) {
+	var (
+		buy   = Button{Action: Action{Type: TextButton, Label: `Buy`}}
+		tests = map[string]struct {
+			template Template
+			valid    bool
+		}{
+			`valid carousel`: {
+				template: NewCarousel(
+					Element{Title: `Tea`, Description: `Green`, PhotoId: `-1_1`, Action: &ElementAction{Type: OpenPhotoElementAction}, Buttons: []Button{buy}},
+					Element{Title: `Coffee`, Description: `Black`, PhotoId: `-1_2`, Action: &ElementAction{Type: OpenLinkElementAction, Link: `https://vk.com`}, Buttons: []Button{buy}},
+				),
+				valid: true,
+			},
+			`photo only`: {
+				template: NewCarousel(Element{PhotoId: `-1_1`, Buttons: []Button{buy}}),
+				valid:    true,
+			},
+			`unknown type`: {
+				template: Template{Type: `list`, Elements: []Element{{PhotoId: `-1_1`, Buttons: []Button{buy}}}},
+			},
+			`no elements`: {
+				template: NewCarousel(),
+			},
+			`too many elements`: {
+				template: NewCarousel(make([]Element, 11)...),
+			},
+			`no buttons`: {
+				template: NewCarousel(Element{PhotoId: `-1_1`}),
+			},
+			`too many buttons`: {
+				template: NewCarousel(Element{PhotoId: `-1_1`, Buttons: []Button{buy, buy, buy, buy}}),
+			},
+			`different buttons count`: {
+				template: NewCarousel(
+					Element{PhotoId: `-1_1`, Buttons: []Button{buy}},
+					Element{PhotoId: `-1_2`, Buttons: []Button{buy, buy}},
+				),
+			},
+			`different fields`: {
+				template: NewCarousel(
+					Element{Title: `Tea`, Description: `Green`, PhotoId: `-1_1`, Buttons: []Button{buy}},
+					Element{Title: `Coffee`, Description: `Black`, Buttons: []Button{buy}},
+				),
+			},
+			`title without description`: {
+				template: NewCarousel(Element{Title: `Tea`, Buttons: []Button{buy}}),
+			},
+			`long title`: {
+				template: NewCarousel(Element{Title: strings.Repeat(`я`, 81), Description: `Green`, Buttons: []Button{buy}}),
+			},
+			`link without url`: {
+				template: NewCarousel(Element{PhotoId: `-1_1`, Action: &ElementAction{Type: OpenLinkElementAction}, Buttons: []Button{buy}}),
+			},
+		}
+	)
+
+	for testName, testCase := range tests {
+		var err = testCase.template.Validate()
+
+		if testCase.valid {
+			assert.Nil(t, err, testName)
+		} else {
+			assert.ErrorIs(t, err, errors.InvalidTemplate, testName)
+		}
+	}
+}
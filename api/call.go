@@ -0,0 +1,248 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/mailru/easyjson"
+	"github.com/sepuka/vkbotserver/errors"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	paramAccessToken  = `access_token`
+	paramVersion      = `v`
+	maskedTokenPrefix = 3
+)
+
+// envelope is the common answer of VK API methods, execute_errors are returned by the execute method only
+type envelope struct {
+	Response      json.RawMessage `json:"response"`
+	Error         *Error          `json:"error"`
+	ExecuteErrors []Error         `json:"execute_errors"`
+}
+
+// Call calls the VK API method and returns the "response" field of the answer or the *Error returned by VK.
+// The group token from the config is used unless params have access_token, the API version is added unless params have v
+// Transient failures are retried according to config.Api.Retry with the same params, so random_id stays the same
+func (a *Api) Call(ctx context.Context, method string, params url.Values) (json.RawMessage, error) {
+	var answer, err = a.invoke(ctx, method, params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return answer.Response, nil
+}
+
+// invoke calls the method with retries and returns the whole answer
+func (a *Api) invoke(ctx context.Context, method string, params url.Values) (*envelope, error) {
+	var (
+		values = url.Values{}
+		answer *envelope
+		delay  time.Duration
+		err    error
+	)
+
+	for key, value := range params {
+		values[key] = value
+	}
+
+	if values.Get(paramAccessToken) == `` {
+		values.Set(paramAccessToken, a.cfg.Api.Token)
+	}
+
+	if values.Get(paramVersion) == `` {
+		values.Set(paramVersion, Version)
+	}
+
+	for attempt := 1; ; attempt++ {
+		if answer, err = a.call(ctx, method, values); err == nil || !a.retry.retryable(err, attempt) {
+			return answer, err
+		}
+
+		if delay = a.retry.delay(attempt); !a.retry.fits(ctx, delay) {
+			return nil, err
+		}
+
+		a.
+			logger.
+			With(
+				zap.String(`method`, method),
+				zap.Int(`attempt`, attempt),
+				zap.Duration(`delay`, delay),
+				zap.Error(err),
+			).
+			Warn(`Api request will be repeated`)
+
+		if a.retry.sleep(ctx, delay) != nil {
+			return nil, err
+		}
+	}
+}
+
+// call makes the single attempt to call the method, params are sent in the form body
+func (a *Api) call(ctx context.Context, method string, values url.Values) (*envelope, error) {
+	var (
+		endpoint     = fmt.Sprintf(`%s/%s`, Endpoint, method)
+		body         = values.Encode()
+		maskedParams = maskToken(endpoint+`?`+body, values.Get(paramAccessToken))
+		request      *http.Request
+		response     *http.Response
+		answer       = &envelope{}
+		dumpResponse []byte
+		err          error
+	)
+
+	if err = a.limiter.wait(ctx, values.Get(paramAccessToken)); err != nil {
+		a.
+			logger.
+			With(
+				zap.String(`request`, maskedParams),
+				zap.Error(err),
+			).
+			Errorf(`Api request rate limited`)
+
+		return nil, err
+	}
+
+	if request, err = http.NewRequestWithContext(ctx, `POST`, endpoint, strings.NewReader(body)); err != nil {
+		a.
+			logger.
+			With(
+				zap.String(`request`, maskedParams),
+				zap.Error(err),
+			).
+			Errorf(`build Api request error`)
+
+		return nil, err
+	}
+
+	request.Header.Set(`Content-Type`, `application/x-www-form-urlencoded`)
+
+	if response, err = a.client.Do(request); err != nil {
+		a.
+			logger.
+			With(
+				zap.String(`request`, maskedParams),
+				zap.Error(err),
+			).
+			Errorf(`send Api request error`)
+
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusInternalServerError {
+		err = errors.NewApiUnavailableError(fmt.Sprintf(`%s answered %d`, method, response.StatusCode))
+
+		a.
+			logger.
+			With(
+				zap.String(`request`, maskedParams),
+				zap.Error(err),
+			).
+			Errorf(`Api is unavailable`)
+
+		return nil, err
+	}
+
+	if dumpResponse, err = httputil.DumpResponse(response, true); err != nil {
+		a.
+			logger.
+			With(
+				zap.String(`request`, maskedParams),
+				zap.Error(err),
+			).
+			Errorf(`dump Api response error`)
+
+		return nil, err
+	}
+
+	a.
+		logger.
+		With(
+			zap.String(`request`, maskedParams),
+			zap.ByteString(`response`, dumpResponse),
+		).
+		Info(`Api request sent`)
+
+	if err = json.NewDecoder(response.Body).Decode(answer); err != nil {
+		a.
+			logger.
+			With(
+				zap.Error(err),
+				zap.ByteString(`response`, dumpResponse),
+			).
+			Error(`error while decoding Api response`)
+
+		return nil, err
+	}
+
+	if answer.Error != nil {
+		a.
+			logger.
+			With(
+				zap.String(`method`, method),
+				zap.Int32(`code`, answer.Error.Code),
+				zap.String(`message`, answer.Error.Message),
+			).
+			Error(`failed Api answer`)
+
+		return nil, answer.Error
+	}
+
+	return answer, nil
+}
+
+// CallInto calls the VK API method and decodes the "response" field of the answer into out
+func (a *Api) CallInto(ctx context.Context, method string, params url.Values, out interface{}) error {
+	var (
+		response json.RawMessage
+		err      error
+	)
+
+	if response, err = a.Call(ctx, method, params); err != nil {
+		return err
+	}
+
+	if unmarshaler, ok := out.(easyjson.Unmarshaler); ok {
+		err = easyjson.Unmarshal(response, unmarshaler)
+	} else {
+		err = json.Unmarshal(response, out)
+	}
+
+	if err != nil {
+		a.
+			logger.
+			With(
+				zap.String(`method`, method),
+				zap.ByteString(`response`, response),
+				zap.Error(err),
+			).
+			Error(`error while decoding Api response`)
+	}
+
+	return err
+}
+
+// maskToken hides all but the first chars of the token, short tokens are hidden entirely
+func maskToken(text string, token string) string {
+	var masked = `...`
+
+	if token == `` {
+		return text
+	}
+
+	if len(token) > maskedTokenPrefix*2 {
+		masked = token[:maskedTokenPrefix] + masked
+	}
+
+	return strings.ReplaceAll(text, token, masked)
+}
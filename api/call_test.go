@@ -0,0 +1,125 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"github.com/sepuka/vkbotserver/api/mocks"
+	"github.com/sepuka/vkbotserver/config"
+	"github.com/sepuka/vkbotserver/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// requestBody returns the form body of the request without consuming it
+func requestBody(req *http.Request) string {
+	var body, _ = req.GetBody()
+	var data, _ = ioutil.ReadAll(body)
+
+	return string(data)
+}
+
+// requestForm returns params of the request form body
+func requestForm(req *http.Request) url.Values {
+	var values, _ = url.ParseQuery(requestBody(req))
+
+	return values
+}
+
+func TestApi_Call(t *testing.T) {
+	var (
+		cfg   = config.Config{Api: config.Api{Token: `group_token`}}
+		tests = map[string]struct {
+			params   url.Values
+			answer   string
+			query    string
+			response string
+			err      *Error
+		}{
+			`group token and version injected`: {
+				params:   url.Values{`user_ids`: {`1`}},
+				answer:   `{"response":[{"id":1}]}`,
+				query:    `access_token=group_token&user_ids=1&v=5.170`,
+				response: `[{"id":1}]`,
+			},
+			`own token and version kept`: {
+				params:   url.Values{`access_token`: {`user_token`}, `v`: {`5.131`}},
+				answer:   `{"response":1}`,
+				query:    `access_token=user_token&v=5.131`,
+				response: `1`,
+			},
+			`api error`: {
+				answer: `{"error":{"error_code":901,"error_msg":"Can't send messages for users without permission","request_params":[{"key":"method","value":"messages.send"}]}}`,
+				query:  `access_token=group_token&v=5.170`,
+				err:    &Error{Code: 901, Message: `Can't send messages for users without permission`, Params: []Params{{Key: `method`, Value: `messages.send`}}},
+			},
+		}
+	)
+
+	for testName, testCase := range tests {
+		var (
+			client = &mocks.HTTPClient{}
+			vk     = NewApi(zap.NewNop().Sugar(), cfg, client, &mocks.Rnder{})
+			query  string
+		)
+
+		client.
+			On(`Do`, mock.MatchedBy(func(req *http.Request) bool {
+				query = requestBody(req)
+				return req.URL.Path == `/method/users.get`
+			})).
+			Once().
+			Return(&http.Response{Body: ioutil.NopCloser(bytes.NewReader([]byte(testCase.answer)))}, nil)
+
+		response, err := vk.Call(context.Background(), `users.get`, testCase.params)
+
+		assert.Equal(t, testCase.query, query, testName)
+		if testCase.err != nil {
+			assert.Equal(t, testCase.err, err, testName)
+			assert.ErrorIs(t, err, errors.ApiCantSendToUser, testName)
+			assert.Nil(t, response, testName)
+		} else {
+			assert.Nil(t, err, testName)
+			assert.JSONEq(t, testCase.response, string(response), testName)
+		}
+	}
+}
+
+func TestApi_CallInto(t *testing.T) {
+	var (
+		client = &mocks.HTTPClient{}
+		vk     = NewApi(zap.NewNop().Sugar(), config.Config{}, client, &mocks.Rnder{})
+		users  []struct {
+			Id        int    `json:"id"`
+			FirstName string `json:"first_name"`
+		}
+	)
+
+	client.
+		On(`Do`, mock.Anything).
+		Once().
+		Return(&http.Response{Body: ioutil.NopCloser(bytes.NewReader([]byte(`{"response":[{"id":1,"first_name":"Pavel"}]}`)))}, nil)
+
+	assert.Nil(t, vk.CallInto(context.Background(), `users.get`, nil, &users))
+	assert.Len(t, users, 1)
+	assert.Equal(t, `Pavel`, users[0].FirstName)
+}
+
+func TestMaskToken(t *testing.T) {
+	var tests = map[string]struct {
+		token    string
+		expected string
+	}{
+		`long token`:  {token: `c991fd1144d1de516fab`, expected: `access_token=c99...&v=5.170`},
+		`short token`: {token: `ab`, expected: `access_token=...&v=5.170`},
+		`no token`:    {token: ``, expected: `access_token=&v=5.170`},
+	}
+
+	for testName, testCase := range tests {
+		assert.Equal(t, testCase.expected, maskToken(`access_token=`+testCase.token+`&v=5.170`, testCase.token), testName)
+	}
+}
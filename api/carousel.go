@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/sepuka/vkbotserver/api/button"
+	"github.com/sepuka/vkbotserver/domain"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// SendCarousel sends the message with the carousel template.
+// If the client can't show carousels the elements are sent as a plain text message with photos attached
+func (a *Api) SendCarousel(peerId int, msg string, template button.Template, info domain.ClientInfo) (MessageRef, error) {
+	return a.SendCarouselContext(context.Background(), peerId, msg, template, info)
+}
+
+// SendCarouselContext is the context-aware variant of the SendCarousel
+func (a *Api) SendCarouselContext(ctx context.Context, peerId int, msg string, template button.Template, info domain.ClientInfo) (MessageRef, error) {
+	var (
+		payload = OutcomeMessage{
+			Message:     msg,
+			AccessToken: a.cfg.Api.Token,
+			ApiVersion:  Version,
+			PeerId:      peerId,
+			RandomId:    a.rnd.Rnd(),
+		}
+		err error
+		js  []byte
+	)
+
+	if err = template.Validate(); err != nil {
+		a.
+			logger.
+			With(
+				zap.Any(`template`, template),
+				zap.Error(err),
+			).
+			Errorf(`invalid carousel template`)
+
+		return MessageRef{}, err
+	}
+
+	if info.IsKnown() && !info.Carousel {
+		payload.Message, payload.Attachment = carouselFallback(msg, template)
+
+		return a.send(ctx, payload)
+	}
+
+	if js, err = json.Marshal(template); err != nil {
+		a.
+			logger.
+			With(
+				zap.Any(`template`, template),
+				zap.Error(err),
+			).
+			Errorf(`build template query string error`)
+
+		return MessageRef{}, err
+	}
+
+	payload.Template = string(js)
+
+	return a.send(ctx, payload)
+}
+
+// carouselFallback returns the text listing the elements and photos of the elements as attachments
+func carouselFallback(msg string, template button.Template) (string, string) {
+	var (
+		lines  = []string{msg}
+		photos []string
+	)
+
+	for _, element := range template.Elements {
+		var item = strings.TrimSpace(element.Title + "\n" + element.Description)
+
+		if element.Action != nil && element.Action.Link != `` {
+			item = strings.TrimSpace(item + "\n" + element.Action.Link)
+		}
+
+		if item != `` {
+			lines = append(lines, item)
+		}
+
+		if element.PhotoId != `` {
+			photos = append(photos, `photo`+element.PhotoId)
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n\n")), strings.Join(photos, `,`)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"bytes"
+	"github.com/sepuka/vkbotserver/api/button"
+	"github.com/sepuka/vkbotserver/api/mocks"
+	"github.com/sepuka/vkbotserver/config"
+	"github.com/sepuka/vkbotserver/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestApi_SendCarousel(t *testing.T) {
+	var (
+		cfg      = config.Config{Api: config.Api{Token: `secret_token`}}
+		buy      = button.Button{Action: button.Action{Type: button.TextButton, Label: `Buy`}}
+		carousel = button.NewCarousel(
+			button.Element{Title: `Tea`, Description: `Green`, PhotoId: `-1_1`, Buttons: []button.Button{buy}},
+			button.Element{Title: `Coffee`, Description: `Black`, PhotoId: `-1_2`, Buttons: []button.Button{buy}},
+		)
+		tests = map[string]struct {
+			info       domain.ClientInfo
+			message    string
+			attachment string
+			template   string
+		}{
+			`carousel supported`: {
+				info:     domain.ClientInfo{ButtonActions: []string{`text`}, Keyboard: true, Carousel: true},
+				message:  `Menu`,
+				template: `{"type":"carousel","elements":[{"title":"Tea","description":"Green","photo_id":"-1_1","buttons":[{"action":{"type":"text","label":"Buy"}}]},{"title":"Coffee","description":"Black","photo_id":"-1_2","buttons":[{"action":{"type":"text","label":"Buy"}}]}]}`,
+			},
+			`unknown client`: {
+				message:  `Menu`,
+				template: `{"type":"carousel","elements":[{"title":"Tea","description":"Green","photo_id":"-1_1","buttons":[{"action":{"type":"text","label":"Buy"}}]},{"title":"Coffee","description":"Black","photo_id":"-1_2","buttons":[{"action":{"type":"text","label":"Buy"}}]}]}`,
+			},
+			`carousel unsupported`: {
+				info:       domain.ClientInfo{ButtonActions: []string{`text`}, Keyboard: true},
+				message:    "Menu\n\nTea\nGreen\n\nCoffee\nBlack",
+				attachment: `photo-1_1,photo-1_2`,
+			},
+		}
+	)
+
+	for testName, testCase := range tests {
+		var (
+			client = &mocks.HTTPClient{}
+			rnd    = &mocks.Rnder{}
+			vk     = NewApi(zap.NewNop().Sugar(), cfg, client, rnd)
+			query  url.Values
+		)
+
+		rnd.On(`Rnd`).Return(int64(1))
+		client.
+			On(`Do`, mock.MatchedBy(func(req *http.Request) bool {
+				query = requestForm(req)
+				return req.URL.Path == `/method/`+MethodApiMessagesSend
+			})).
+			Once().
+			Return(&http.Response{Body: ioutil.NopCloser(bytes.NewReader([]byte(`{"response":[{"peer_id":557404793,"message_id":100,"conversation_message_id":5}]}`)))}, nil)
+
+		ref, err := vk.SendCarousel(557404793, `Menu`, carousel, testCase.info)
+		assert.Nil(t, err, testName)
+		assert.Equal(t, MessageRef{PeerId: 557404793, MessageId: 100, ConversationMessageId: 5}, ref, testName)
+		assert.Equal(t, testCase.message, query.Get(`message`), testName)
+		assert.Equal(t, testCase.attachment, query.Get(`attachment`), testName)
+		assert.Equal(t, testCase.template, query.Get(`template`), testName)
+	}
+}
+
+func TestApi_SendCarouselInvalid(t *testing.T) {
+	var (
+		client = &mocks.HTTPClient{}
+		rnd    = &mocks.Rnder{}
+		vk     = NewApi(zap.NewNop().Sugar(), config.Config{}, client, rnd)
+	)
+
+	rnd.On(`Rnd`).Return(int64(1))
+
+	_, err := vk.SendCarousel(557404793, `Menu`, button.NewCarousel(), domain.ClientInfo{})
+	assert.NotNil(t, err)
+	client.AssertNotCalled(t, `Do`, mock.Anything)
+}
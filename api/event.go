@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"go.uber.org/zap"
+)
+
+const (
+	EventDataShowSnackbar = `show_snackbar`
+	EventDataOpenLink     = `open_link`
+	EventDataOpenApp      = `open_app`
+)
+
+type (
+	// EventData is the action which VK client does after the callback button is answered
+	// see https://dev.vk.com/method/messages.sendMessageEventAnswer
+	EventData struct {
+		Type    string `json:"type"`
+		Text    string `json:"text,omitempty"`
+		Link    string `json:"link,omitempty"`
+		AppId   int    `json:"app_id,omitempty"`
+		OwnerId int    `json:"owner_id,omitempty"`
+		Hash    string `json:"hash,omitempty"`
+	}
+
+	MessageEventAnswer struct {
+		AccessToken string `url:"access_token"`
+		ApiVersion  string `url:"v"`
+		EventId     string `url:"event_id"`
+		UserId      int    `url:"user_id"`
+		PeerId      int    `url:"peer_id"`
+		EventData   string `url:"event_data,omitempty"`
+	}
+)
+
+// ShowSnackbar shows the text in the popup, the text is up to 90 chars
+func ShowSnackbar(text string) *EventData {
+	return &EventData{
+		Type: EventDataShowSnackbar,
+		Text: text,
+	}
+}
+
+// OpenLink opens the link
+func OpenLink(link string) *EventData {
+	return &EventData{
+		Type: EventDataOpenLink,
+		Link: link,
+	}
+}
+
+// OpenApp opens the VK Mini App, ownerId is optional
+func OpenApp(appId int, ownerId int, hash string) *EventData {
+	return &EventData{
+		Type:    EventDataOpenApp,
+		AppId:   appId,
+		OwnerId: ownerId,
+		Hash:    hash,
+	}
+}
+
+// SendMessageEventAnswer answers the callback button pressing, nil data just stops the button loader
+func (a *Api) SendMessageEventAnswer(eventId string, userId int, peerId int, data *EventData) error {
+	return a.SendMessageEventAnswerContext(context.Background(), eventId, userId, peerId, data)
+}
+
+// SendMessageEventAnswerContext is the context-aware variant of the SendMessageEventAnswer
+func (a *Api) SendMessageEventAnswerContext(ctx context.Context, eventId string, userId int, peerId int, data *EventData) error {
+	var (
+		payload = MessageEventAnswer{
+			AccessToken: a.cfg.Api.Token,
+			ApiVersion:  Version,
+			EventId:     eventId,
+			UserId:      userId,
+			PeerId:      peerId,
+		}
+		err error
+		js  []byte
+	)
+
+	if data != nil {
+		if js, err = json.Marshal(data); err != nil {
+			a.
+				logger.
+				With(
+					zap.Any(`request`, data),
+					zap.Error(err),
+				).
+				Errorf(`build event data query string error`)
+
+			return err
+		}
+
+		payload.EventData = string(js)
+	}
+
+	return a.request(ctx, MethodApiMessagesSendMessageEventAnswer, payload)
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"bytes"
+	"github.com/sepuka/vkbotserver/api/mocks"
+	"github.com/sepuka/vkbotserver/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestApi_SendMessageEventAnswer(t *testing.T) {
+	var (
+		cfg   = config.Config{Api: config.Api{Token: `secret_token`}}
+		tests = map[string]struct {
+			data     *EventData
+			expected string
+		}{
+			`without data`: {
+				expected: `access_token=secret_token&event_id=e6ba7d6a3c3f&peer_id=2000000001&user_id=557404793&v=5.170`,
+			},
+			`snackbar`: {
+				data:     ShowSnackbar(`Liked`),
+				expected: `access_token=secret_token&event_data=%7B%22type%22%3A%22show_snackbar%22%2C%22text%22%3A%22Liked%22%7D&event_id=e6ba7d6a3c3f&peer_id=2000000001&user_id=557404793&v=5.170`,
+			},
+			`open link`: {
+				data:     OpenLink(`https://vk.com`),
+				expected: `access_token=secret_token&event_data=%7B%22type%22%3A%22open_link%22%2C%22link%22%3A%22https%3A%2F%2Fvk.com%22%7D&event_id=e6ba7d6a3c3f&peer_id=2000000001&user_id=557404793&v=5.170`,
+			},
+			`open app`: {
+				data:     OpenApp(6232540, -1, `start`),
+				expected: `access_token=secret_token&event_data=%7B%22type%22%3A%22open_app%22%2C%22app_id%22%3A6232540%2C%22owner_id%22%3A-1%2C%22hash%22%3A%22start%22%7D&event_id=e6ba7d6a3c3f&peer_id=2000000001&user_id=557404793&v=5.170`,
+			},
+		}
+	)
+
+	for testName, testCase := range tests {
+		var (
+			client = &mocks.HTTPClient{}
+			vk     = NewApi(zap.NewNop().Sugar(), cfg, client, &mocks.Rnder{})
+			query  string
+		)
+
+		client.
+			On(`Do`, mock.MatchedBy(func(req *http.Request) bool {
+				query = requestBody(req)
+				return req.URL.Path == `/method/`+MethodApiMessagesSendMessageEventAnswer
+			})).
+			Once().
+			Return(&http.Response{Body: ioutil.NopCloser(bytes.NewReader([]byte(`{"response":1}`)))}, nil)
+
+		assert.Nil(t, vk.SendMessageEventAnswer(`e6ba7d6a3c3f`, 557404793, 2000000001, testCase.data), testName)
+		assert.Equal(t, testCase.expected, query, testName)
+		client.AssertExpectations(t)
+	}
+}
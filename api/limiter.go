@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"github.com/sepuka/vkbotserver/config"
+	"github.com/sepuka/vkbotserver/errors"
+	"math"
+	"sync"
+	"time"
+)
+
+type (
+	// LimiterStats describes how the rate limiter has delayed API calls
+	LimiterStats struct {
+		Calls     uint64
+		Delayed   uint64
+		Rejected  uint64
+		TotalWait time.Duration
+		MaxWait   time.Duration
+	}
+
+	bucket struct {
+		cfg    config.RateLimit
+		tokens float64
+		last   time.Time
+	}
+
+	// limiter keeps a token bucket per access token, it's shared by all methods and goroutines of the Api
+	limiter struct {
+		mu      sync.Mutex
+		group   string
+		cfg     config.Api
+		buckets map[string]*bucket
+		stats   LimiterStats
+		now     func() time.Time
+		sleep   func(ctx context.Context, d time.Duration) error
+	}
+)
+
+func newLimiter(cfg config.Api) *limiter {
+	return &limiter{
+		group:   cfg.Token,
+		cfg:     cfg,
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+		sleep:   sleep,
+	}
+}
+
+// wait takes a slot for the call with the token, it waits for the slot or fails if the limit is non-blocking
+func (l *limiter) wait(ctx context.Context, token string) error {
+	var (
+		delay time.Duration
+		err   error
+	)
+
+	if delay, err = l.reserve(token); err != nil || delay == 0 {
+		return err
+	}
+
+	if err = l.sleep(ctx, delay); err != nil {
+		l.cancel(token)
+	}
+
+	return err
+}
+
+// reserve takes a token from the bucket and returns the time to wait until the token is really available
+func (l *limiter) reserve(token string) (time.Duration, error) {
+	var (
+		now = l.now()
+		b   *bucket
+		ok  bool
+	)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok = l.buckets[token]; !ok {
+		if b = l.newBucket(token, now); b.cfg.Rate <= 0 {
+			return 0, nil
+		}
+
+		l.prune(now)
+		l.buckets[token] = b
+	}
+
+	l.stats.Calls++
+
+	b.tokens = math.Min(b.burst(), b.tokens+now.Sub(b.last).Seconds()*b.cfg.Rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, nil
+	}
+
+	if !b.cfg.Block {
+		l.stats.Rejected++
+		return 0, errors.NewRateLimitedError(fmt.Sprintf(`more than %g API calls per second`, b.cfg.Rate))
+	}
+
+	var delay = time.Duration((1 - b.tokens) / b.cfg.Rate * float64(time.Second))
+
+	b.tokens--
+	l.stats.Delayed++
+	l.stats.TotalWait += delay
+	if delay > l.stats.MaxWait {
+		l.stats.MaxWait = delay
+	}
+
+	return delay, nil
+}
+
+// cancel returns the reserved token to the bucket if the call was canceled while waiting
+func (l *limiter) cancel(token string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok := l.buckets[token]; ok {
+		b.tokens++
+	}
+}
+
+func (l *limiter) newBucket(token string, now time.Time) *bucket {
+	var b = &bucket{
+		cfg:  l.cfg.UserRateLimit,
+		last: now,
+	}
+
+	if token == l.group {
+		b.cfg = l.cfg.RateLimit
+	}
+
+	b.tokens = b.burst()
+
+	return b
+}
+
+// prune forgets buckets which are full already, they are the same as new ones
+func (l *limiter) prune(now time.Time) {
+	for token, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*b.cfg.Rate >= b.burst() {
+			delete(l.buckets, token)
+		}
+	}
+}
+
+func (l *limiter) statistics() LimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.stats
+}
+
+func (b *bucket) burst() float64 {
+	if b.cfg.Burst > 0 {
+		return float64(b.cfg.Burst)
+	}
+
+	return math.Max(1, math.Ceil(b.cfg.Rate))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	var timer = time.NewTimer(d)
+
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
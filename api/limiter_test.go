@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"github.com/sepuka/vkbotserver/config"
+	"github.com/sepuka/vkbotserver/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newTestLimiter(cfg config.Api) (*limiter, *[]time.Duration) {
+	var (
+		l      = newLimiter(cfg)
+		now    = time.Date(2022, 5, 20, 12, 0, 0, 0, time.UTC)
+		sleeps = &[]time.Duration{}
+	)
+
+	l.now = func() time.Time { return now }
+	l.sleep = func(ctx context.Context, d time.Duration) error {
+		*sleeps = append(*sleeps, d)
+		return ctx.Err()
+	}
+
+	return l, sleeps
+}
+
+func TestLimiter_WaitBlocking(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		l, sleeps = newTestLimiter(config.Api{
+			Token:     `group_token`,
+			RateLimit: config.RateLimit{Rate: 20, Burst: 2, Block: true},
+		})
+	)
+
+	assert.Nil(t, l.wait(ctx, `group_token`))
+	assert.Nil(t, l.wait(ctx, `group_token`))
+	assert.Empty(t, *sleeps, `burst is not delayed`)
+
+	assert.Nil(t, l.wait(ctx, `group_token`))
+	assert.Nil(t, l.wait(ctx, `group_token`))
+	assert.Equal(t, []time.Duration{50 * time.Millisecond, 100 * time.Millisecond}, *sleeps)
+
+	assert.Equal(t, LimiterStats{Calls: 4, Delayed: 2, TotalWait: 150 * time.Millisecond, MaxWait: 100 * time.Millisecond}, l.statistics())
+}
+
+func TestLimiter_WaitFailFast(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		l, _ = newTestLimiter(config.Api{
+			Token:         `group_token`,
+			UserRateLimit: config.RateLimit{Rate: 3},
+		})
+		err error
+	)
+
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, l.wait(ctx, `user_token`))
+	}
+
+	err = l.wait(ctx, `user_token`)
+	assert.ErrorIs(t, err, errors.RateLimited)
+	assert.True(t, errors.IsRetryable(err))
+
+	assert.Nil(t, l.wait(ctx, `other_user_token`), `each user token has its own bucket`)
+
+	for i := 0; i < 10; i++ {
+		assert.Nil(t, l.wait(ctx, `group_token`), `group token is not limited`)
+	}
+
+	assert.Equal(t, uint64(1), l.statistics().Rejected)
+}
+
+func TestLimiter_WaitCanceled(t *testing.T) {
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		l, _        = newTestLimiter(config.Api{
+			Token:     `group_token`,
+			RateLimit: config.RateLimit{Rate: 1, Block: true},
+		})
+	)
+
+	assert.Nil(t, l.wait(ctx, `group_token`))
+
+	cancel()
+	assert.ErrorIs(t, l.wait(ctx, `group_token`), context.Canceled)
+	assert.Equal(t, float64(0), l.buckets[`group_token`].tokens, `canceled call gives the slot back`)
+}
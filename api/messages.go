@@ -0,0 +1,201 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/sepuka/vkbotserver/api/button"
+	"github.com/sepuka/vkbotserver/domain"
+	"go.uber.org/zap"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	MethodApiMessagesEdit                       = `messages.edit`
+	MethodApiMessagesDelete                     = `messages.delete`
+	MethodApiMessagesPin                        = `messages.pin`
+	MethodApiMessagesUnpin                      = `messages.unpin`
+	MethodApiMessagesGetByConversationMessageId = `messages.getByConversationMessageId`
+	// MaxPeerIds is the VK limit of peers which messages.send accepts by one call
+	MaxPeerIds = 100
+)
+
+type (
+	// MessageRef identifies the message. Group bots address messages in chats by the peer and the conversation message id,
+	// the message id is known for private messages only
+	MessageRef struct {
+		PeerId                int `json:"peer_id"`
+		MessageId             int `json:"message_id"`
+		ConversationMessageId int `json:"conversation_message_id"`
+	}
+
+	// SendResult is the result of messages.send for one of the peers, Error is set if the message is not sent
+	SendResult struct {
+		MessageRef
+		Error *Error `json:"error"`
+	}
+
+	conversationMessages struct {
+		Count int              `json:"count"`
+		Items []domain.Message `json:"items"`
+	}
+)
+
+// params returns peer_id and conversation_message_id if it's known or message_id otherwise
+func (r MessageRef) params() url.Values {
+	var params = url.Values{`peer_id`: {strconv.Itoa(r.PeerId)}}
+
+	if r.ConversationMessageId != 0 {
+		params.Set(`conversation_message_id`, strconv.Itoa(r.ConversationMessageId))
+	} else {
+		params.Set(`message_id`, strconv.Itoa(r.MessageId))
+	}
+
+	return params
+}
+
+// EditMessage replaces text, attachments and keyboard of the message, nil keyboard keeps the message without keyboard
+func (a *Api) EditMessage(ref MessageRef, msg string, attachment string, keyboard *button.Keyboard) error {
+	return a.EditMessageContext(context.Background(), ref, msg, attachment, keyboard)
+}
+
+// EditMessageContext is the context-aware variant of the EditMessage
+func (a *Api) EditMessageContext(ctx context.Context, ref MessageRef, msg string, attachment string, keyboard *button.Keyboard) error {
+	var (
+		params = ref.params()
+		js     []byte
+		err    error
+	)
+
+	params.Set(`message`, msg)
+	params.Set(`keep_forward_messages`, `1`)
+
+	if attachment != `` {
+		params.Set(`attachment`, attachment)
+	}
+
+	if keyboard != nil {
+		if js, err = json.Marshal(keyboard); err != nil {
+			a.
+				logger.
+				With(
+					zap.Any(`request`, keyboard),
+					zap.Error(err),
+				).
+				Errorf(`build keyboard query string error`)
+
+			return err
+		}
+
+		params.Set(`keyboard`, string(js))
+	}
+
+	_, err = a.Call(ctx, MethodApiMessagesEdit, params)
+
+	return err
+}
+
+// DeleteMessages deletes messages of the same peer, deleteForAll deletes them for all chat members
+func (a *Api) DeleteMessages(refs []MessageRef, deleteForAll bool) error {
+	return a.DeleteMessagesContext(context.Background(), refs, deleteForAll)
+}
+
+// DeleteMessagesContext is the context-aware variant of the DeleteMessages
+func (a *Api) DeleteMessagesContext(ctx context.Context, refs []MessageRef, deleteForAll bool) error {
+	var (
+		params     = url.Values{}
+		ids        = make([]string, 0, len(refs))
+		byMessage  = true
+		peerId     int
+		identifier int
+		err        error
+	)
+
+	if len(refs) == 0 {
+		return nil
+	}
+
+	peerId = refs[0].PeerId
+
+	for _, ref := range refs {
+		byMessage = byMessage && ref.MessageId != 0
+	}
+
+	for _, ref := range refs {
+		if identifier = ref.ConversationMessageId; byMessage {
+			identifier = ref.MessageId
+		} else if ref.PeerId != peerId || identifier == 0 {
+			return fmt.Errorf(`messages without ids must have conversation message ids of the same peer`)
+		}
+
+		ids = append(ids, strconv.Itoa(identifier))
+	}
+
+	if byMessage {
+		params.Set(`message_ids`, strings.Join(ids, `,`))
+	} else {
+		params.Set(`peer_id`, strconv.Itoa(peerId))
+		params.Set(`cmids`, strings.Join(ids, `,`))
+	}
+
+	if deleteForAll {
+		params.Set(`delete_for_all`, `1`)
+	}
+
+	_, err = a.Call(ctx, MethodApiMessagesDelete, params)
+
+	return err
+}
+
+// PinMessage pins the message in the chat
+func (a *Api) PinMessage(ref MessageRef) error {
+	return a.PinMessageContext(context.Background(), ref)
+}
+
+// PinMessageContext is the context-aware variant of the PinMessage
+func (a *Api) PinMessageContext(ctx context.Context, ref MessageRef) error {
+	var _, err = a.Call(ctx, MethodApiMessagesPin, ref.params())
+
+	return err
+}
+
+// UnpinMessage unpins the pinned message of the chat
+func (a *Api) UnpinMessage(peerId int) error {
+	return a.UnpinMessageContext(context.Background(), peerId)
+}
+
+// UnpinMessageContext is the context-aware variant of the UnpinMessage
+func (a *Api) UnpinMessageContext(ctx context.Context, peerId int) error {
+	var _, err = a.Call(ctx, MethodApiMessagesUnpin, url.Values{`peer_id`: {strconv.Itoa(peerId)}})
+
+	return err
+}
+
+// GetByConversationMessageId returns messages of the peer by their conversation message ids
+func (a *Api) GetByConversationMessageId(peerId int, conversationMessageIds ...int) ([]domain.Message, error) {
+	return a.GetByConversationMessageIdContext(context.Background(), peerId, conversationMessageIds...)
+}
+
+// GetByConversationMessageIdContext is the context-aware variant of the GetByConversationMessageId
+func (a *Api) GetByConversationMessageIdContext(ctx context.Context, peerId int, conversationMessageIds ...int) ([]domain.Message, error) {
+	var (
+		ids      = make([]string, 0, len(conversationMessageIds))
+		messages = &conversationMessages{}
+		err      error
+	)
+
+	for _, id := range conversationMessageIds {
+		ids = append(ids, strconv.Itoa(id))
+	}
+
+	if err = a.CallInto(ctx, MethodApiMessagesGetByConversationMessageId, url.Values{
+		`peer_id`:                  {strconv.Itoa(peerId)},
+		`conversation_message_ids`: {strings.Join(ids, `,`)},
+	}, messages); err != nil {
+		return nil, err
+	}
+
+	return messages.Items, nil
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"github.com/sepuka/vkbotserver/api/mocks"
+	"github.com/sepuka/vkbotserver/config"
+	"github.com/sepuka/vkbotserver/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func newMessagesApi(client *mocks.HTTPClient, form *url.Values, method string, body string) *Api {
+	var rnd = &mocks.Rnder{}
+
+	rnd.On(`Rnd`).Return(int64(1))
+	client.
+		On(`Do`, mock.Anything).
+		Once().
+		Run(func(args mock.Arguments) {
+			var req = args.Get(0).(*http.Request)
+
+			if req.URL.Path == `/method/`+method {
+				*form = requestForm(req)
+			}
+		}).
+		Return(answer(http.StatusOK, body), nil)
+
+	return NewApi(zap.NewNop().Sugar(), config.Config{}, client, rnd)
+}
+
+func TestApi_SendMessage(t *testing.T) {
+	var (
+		tests = map[string]struct {
+			body     string
+			expected MessageRef
+			err      error
+		}{
+			`sent`: {
+				body:     `{"response":[{"peer_id":2000000001,"message_id":0,"conversation_message_id":5}]}`,
+				expected: MessageRef{PeerId: 2000000001, ConversationMessageId: 5},
+			},
+			`not allowed`: {
+				body:     `{"response":[{"peer_id":2000000001,"error":{"error_code":901,"error_msg":"Can't send messages for users without permission"}}]}`,
+				expected: MessageRef{PeerId: 2000000001},
+				err:      errors.ApiCantSendToUser,
+			},
+		}
+	)
+
+	for testName, testCase := range tests {
+		var (
+			client = &mocks.HTTPClient{}
+			form   url.Values
+			vk     = newMessagesApi(client, &form, MethodApiMessagesSend, testCase.body)
+		)
+
+		ref, err := vk.SendMessage(2000000001, `hello`)
+
+		if testCase.err == nil {
+			assert.Nil(t, err, testName)
+		} else {
+			assert.ErrorIs(t, err, testCase.err, testName)
+		}
+		assert.Equal(t, testCase.expected, ref, testName)
+		assert.Equal(t, `2000000001`, form.Get(`peer_ids`), testName)
+		assert.Empty(t, form.Get(`peer_id`), testName)
+	}
+}
+
+func TestApi_EditMessage(t *testing.T) {
+	var (
+		tests = map[string]struct {
+			ref                   MessageRef
+			messageId             string
+			conversationMessageId string
+		}{
+			`by conversation message id`: {
+				ref:                   MessageRef{PeerId: 2000000001, MessageId: 7, ConversationMessageId: 5},
+				conversationMessageId: `5`,
+			},
+			`by message id`: {
+				ref:       MessageRef{PeerId: 557404793, MessageId: 7},
+				messageId: `7`,
+			},
+		}
+	)
+
+	for testName, testCase := range tests {
+		var (
+			client = &mocks.HTTPClient{}
+			form   url.Values
+			vk     = newMessagesApi(client, &form, MethodApiMessagesEdit, `{"response":1}`)
+		)
+
+		assert.Nil(t, vk.EditMessage(testCase.ref, `edited`, ``, nil), testName)
+		assert.Equal(t, `edited`, form.Get(`message`), testName)
+		assert.Equal(t, testCase.messageId, form.Get(`message_id`), testName)
+		assert.Equal(t, testCase.conversationMessageId, form.Get(`conversation_message_id`), testName)
+		assert.Empty(t, form.Get(`keyboard`), testName)
+	}
+}
+
+func TestApi_DeleteMessages(t *testing.T) {
+	var (
+		tests = map[string]struct {
+			refs     []MessageRef
+			forAll   bool
+			expected url.Values
+		}{
+			`by message ids`: {
+				refs: []MessageRef{{PeerId: 557404793, MessageId: 7}, {PeerId: 557404793, MessageId: 8}},
+				expected: url.Values{
+					`message_ids`: {`7,8`},
+				},
+			},
+			`by conversation message ids for all`: {
+				refs:   []MessageRef{{PeerId: 2000000001, ConversationMessageId: 5}, {PeerId: 2000000001, MessageId: 9, ConversationMessageId: 6}},
+				forAll: true,
+				expected: url.Values{
+					`peer_id`:        {`2000000001`},
+					`cmids`:          {`5,6`},
+					`delete_for_all`: {`1`},
+				},
+			},
+		}
+	)
+
+	for testName, testCase := range tests {
+		var (
+			client = &mocks.HTTPClient{}
+			form   url.Values
+			vk     = newMessagesApi(client, &form, MethodApiMessagesDelete, `{"response":{"5":1,"6":1}}`)
+		)
+
+		assert.Nil(t, vk.DeleteMessages(testCase.refs, testCase.forAll), testName)
+
+		form.Del(paramVersion)
+		form.Del(paramAccessToken)
+		assert.Equal(t, testCase.expected, form, testName)
+	}
+}
+
+func TestApi_DeleteMessagesOfDifferentPeers(t *testing.T) {
+	var (
+		client = &mocks.HTTPClient{}
+		vk     = NewApi(zap.NewNop().Sugar(), config.Config{}, client, &mocks.Rnder{})
+		refs   = []MessageRef{{PeerId: 2000000001, ConversationMessageId: 5}, {PeerId: 2000000002, ConversationMessageId: 5}}
+	)
+
+	assert.NotNil(t, vk.DeleteMessages(refs, false))
+	client.AssertNotCalled(t, `Do`, mock.Anything)
+}
+
+func TestApi_GetByConversationMessageId(t *testing.T) {
+	var (
+		client = &mocks.HTTPClient{}
+		form   url.Values
+		vk     = newMessagesApi(client, &form, MethodApiMessagesGetByConversationMessageId, `{"response":{"count":2,"items":[{"id":0,"peer_id":2000000001,"text":"first"},{"id":0,"peer_id":2000000001,"text":"second"}]}}`)
+	)
+
+	messages, err := vk.GetByConversationMessageId(2000000001, 5, 6)
+
+	assert.Nil(t, err)
+	assert.Equal(t, `5,6`, form.Get(`conversation_message_ids`))
+	assert.Equal(t, `2000000001`, form.Get(`peer_id`))
+	assert.Len(t, messages, 2)
+	assert.Equal(t, `second`, messages[1].Text)
+}
@@ -1,15 +1,12 @@
 package api
 
+import "github.com/sepuka/vkbotserver/errors"
+
 type (
-	Params struct {
-		Key   string
-		Value string
-	}
-	Error struct {
-		Code    int32    `json:"error_code"`
-		Message string   `json:"error_msg"`
-		Params  []Params `json:"request_params"`
-	}
+	// Params is kept for compatibility, it's the errors.ApiParam
+	Params = errors.ApiParam
+	// Error is kept for compatibility, it's the errors.ApiError
+	Error = errors.ApiError
 
 	Response struct {
 		Error    Error
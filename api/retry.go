@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"github.com/sepuka/vkbotserver/config"
+	"github.com/sepuka/vkbotserver/errors"
+	"math/rand"
+	"time"
+)
+
+// retrier decides whether and when a failed API call is repeated
+type retrier struct {
+	cfg    config.Retry
+	now    func() time.Time
+	sleep  func(ctx context.Context, d time.Duration) error
+	jitter func(max time.Duration) time.Duration
+}
+
+func newRetrier(cfg config.Retry) *retrier {
+	return &retrier{
+		cfg:    cfg,
+		now:    time.Now,
+		sleep:  sleep,
+		jitter: jitter,
+	}
+}
+
+// retryable tells whether the call is repeated, rejections of the fail-fast limiter are returned at once
+// as the caller asked not to wait
+func (r *retrier) retryable(err error, attempt int) bool {
+	return attempt < r.cfg.MaxAttempts && errors.IsRetryable(err) && !errors.IsRateLimited(err)
+}
+
+// delay is the exponential backoff before the next attempt plus the jitter up to a half of it
+func (r *retrier) delay(attempt int) time.Duration {
+	var backoff = r.cfg.Backoff
+
+	for i := 1; i < attempt && (r.cfg.MaxBackoff <= 0 || backoff < r.cfg.MaxBackoff); i++ {
+		backoff *= 2
+	}
+
+	if r.cfg.MaxBackoff > 0 && backoff > r.cfg.MaxBackoff {
+		backoff = r.cfg.MaxBackoff
+	}
+
+	return backoff + r.jitter(backoff/2)
+}
+
+// fits tells whether the next attempt may start before the context deadline
+func (r *retrier) fits(ctx context.Context, delay time.Duration) bool {
+	var deadline, ok = ctx.Deadline()
+
+	return ctx.Err() == nil && (!ok || r.now().Add(delay).Before(deadline))
+}
+
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(max)))
+}
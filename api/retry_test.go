@@ -0,0 +1,133 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"github.com/sepuka/vkbotserver/api/mocks"
+	"github.com/sepuka/vkbotserver/config"
+	"github.com/sepuka/vkbotserver/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newRetryApi(client HTTPClient, rnd Rnder, sleeps *[]time.Duration) *Api {
+	var (
+		cfg = config.Config{Api: config.Api{
+			Token: `group_token`,
+			Retry: config.Retry{MaxAttempts: 3, Backoff: 100 * time.Millisecond, MaxBackoff: 150 * time.Millisecond},
+		}}
+		vk = NewApi(zap.NewNop().Sugar(), cfg, client, rnd)
+	)
+
+	vk.retry.jitter = func(max time.Duration) time.Duration { return max }
+	vk.retry.sleep = func(ctx context.Context, d time.Duration) error {
+		*sleeps = append(*sleeps, d)
+		return nil
+	}
+
+	return vk
+}
+
+func answer(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Body: ioutil.NopCloser(bytes.NewReader([]byte(body)))}
+}
+
+func TestApi_CallRetry(t *testing.T) {
+	var (
+		client    = &mocks.HTTPClient{}
+		rnd       = &mocks.Rnder{}
+		sleeps    []time.Duration
+		randomIds []string
+		vk        = newRetryApi(client, rnd, &sleeps)
+		record    = func(args mock.Arguments) {
+			randomIds = append(randomIds, requestForm(args.Get(0).(*http.Request)).Get(`random_id`))
+		}
+	)
+
+	rnd.On(`Rnd`).Once().Return(int64(42))
+	client.On(`Do`, mock.Anything).Once().Run(record).Return(answer(http.StatusOK, `{"error":{"error_code":6,"error_msg":"Too many requests per second"}}`), nil)
+	client.On(`Do`, mock.Anything).Once().Run(record).Return(answer(http.StatusBadGateway, `<html>Bad Gateway</html>`), nil)
+	client.On(`Do`, mock.Anything).Once().Run(record).Return(answer(http.StatusOK, `{"response":[{"peer_id":557404793,"message_id":100}]}`), nil)
+
+	_, err := vk.SendMessage(557404793, `hello`)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{`42`, `42`, `42`}, randomIds, `random_id is the same for all attempts`)
+	assert.Equal(t, []time.Duration{150 * time.Millisecond, 225 * time.Millisecond}, sleeps, `backoff is capped and the jitter is added`)
+	client.AssertExpectations(t)
+}
+
+func TestApi_CallNoRetry(t *testing.T) {
+	var (
+		tests = map[string]struct {
+			ctx      func() (context.Context, context.CancelFunc)
+			body     string
+			attempts int
+			expected error
+		}{
+			`permanent error`: {
+				ctx:      func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+				body:     `{"error":{"error_code":901,"error_msg":"Can't send messages for users without permission"}}`,
+				attempts: 1,
+				expected: errors.ApiCantSendToUser,
+			},
+			`attempts exhausted`: {
+				ctx:      func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+				body:     `{"error":{"error_code":10,"error_msg":"Internal server error"}}`,
+				attempts: 3,
+				expected: errors.ApiInternal,
+			},
+			`deadline is too close`: {
+				ctx: func() (context.Context, context.CancelFunc) {
+					return context.WithTimeout(context.Background(), 50*time.Millisecond)
+				},
+				body:     `{"error":{"error_code":9,"error_msg":"Flood control"}}`,
+				attempts: 1,
+				expected: errors.ApiFloodControl,
+			},
+		}
+	)
+
+	for testName, testCase := range tests {
+		var (
+			client      = &mocks.HTTPClient{}
+			sleeps      []time.Duration
+			vk          = newRetryApi(client, &mocks.Rnder{}, &sleeps)
+			ctx, cancel = testCase.ctx()
+		)
+
+		client.On(`Do`, mock.Anything).Times(testCase.attempts).Return(func(*http.Request) *http.Response {
+			return answer(http.StatusOK, testCase.body)
+		}, nil)
+
+		_, err := vk.Call(ctx, `messages.send`, nil)
+
+		assert.ErrorIs(t, err, testCase.expected, testName)
+		client.AssertNumberOfCalls(t, `Do`, testCase.attempts)
+		cancel()
+	}
+}
+
+func TestApi_CallNoRetryRateLimited(t *testing.T) {
+	var (
+		client = &mocks.HTTPClient{}
+		sleeps []time.Duration
+		vk     = newRetryApi(client, &mocks.Rnder{}, &sleeps)
+	)
+
+	vk.limiter = newLimiter(config.Api{Token: `group_token`, RateLimit: config.RateLimit{Rate: 1}})
+	client.On(`Do`, mock.Anything).Once().Return(answer(http.StatusOK, `{"response":1}`), nil)
+
+	_, err := vk.Call(context.Background(), `messages.markAsRead`, nil)
+	assert.Nil(t, err)
+
+	_, err = vk.Call(context.Background(), `messages.markAsRead`, nil)
+	assert.ErrorIs(t, err, errors.RateLimited)
+	assert.Empty(t, sleeps, `the fail-fast limiter rejection is not retried`)
+	assert.Equal(t, uint64(1), vk.LimiterStats().Rejected, `the limiter is asked once`)
+	client.AssertExpectations(t)
+}
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"hash/fnv"
+	"math"
 	"math/rand"
+	"strings"
 	"time"
 )
 
@@ -23,3 +26,12 @@ func (r *rnd) Rnd() int64 {
 
 	return rand.Int63()
 }
+
+// StableRandomId derives the positive random_id from the parts, VK drops a message sent again with the same parts
+func StableRandomId(parts ...string) int64 {
+	var hash = fnv.New64a()
+
+	_, _ = hash.Write([]byte(strings.Join(parts, `:`)))
+
+	return int64(hash.Sum64()&math.MaxInt64) | 1
+}
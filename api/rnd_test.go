@@ -0,0 +1,14 @@
+package api
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStableRandomId(t *testing.T) {
+	var id = StableRandomId(`news`, `100`)
+
+	assert.Equal(t, id, StableRandomId(`news`, `100`), `the same parts give the same id`)
+	assert.NotEqual(t, id, StableRandomId(`news`, `200`))
+	assert.Positive(t, id)
+}
@@ -0,0 +1,296 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/sepuka/vkbotserver/errors"
+	"go.uber.org/zap"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+const (
+	MethodApiPhotosGetMessagesUploadServer = `photos.getMessagesUploadServer`
+	MethodApiPhotosSaveMessagesPhoto       = `photos.saveMessagesPhoto`
+	MethodApiDocsGetMessagesUploadServer   = `docs.getMessagesUploadServer`
+	MethodApiDocsSave                      = `docs.save`
+
+	DocTypeDoc          = `doc`
+	DocTypeAudioMessage = `audio_message`
+
+	uploadKindPhoto = `photo`
+)
+
+type (
+	// UploadCache keeps attachments of uploaded files by the content hash
+	UploadCache interface {
+		Get(hash string) (string, bool)
+		Set(hash string, attachment string)
+	}
+
+	memoryUploadCache struct {
+		mu          sync.RWMutex
+		attachments map[string]string
+	}
+
+	// Uploader uploads files for outgoing messages and returns attachments like photo-1_2 or doc-1_2
+	Uploader struct {
+		api   *Api
+		cache UploadCache
+	}
+
+	uploadServer struct {
+		UploadUrl string `json:"upload_url"`
+	}
+
+	photoUpload struct {
+		Server int    `json:"server"`
+		Photo  string `json:"photo"`
+		Hash   string `json:"hash"`
+	}
+
+	docUpload struct {
+		File  string `json:"file"`
+		Error string `json:"error"`
+	}
+
+	savedMedia struct {
+		Id        int    `json:"id"`
+		OwnerId   int    `json:"owner_id"`
+		AccessKey string `json:"access_key"`
+	}
+
+	savedDoc struct {
+		Type         string      `json:"type"`
+		Doc          *savedMedia `json:"doc"`
+		AudioMessage *savedMedia `json:"audio_message"`
+	}
+)
+
+// NewMemoryUploadCache creates cache which keeps attachments in the process memory
+func NewMemoryUploadCache() *memoryUploadCache {
+	return &memoryUploadCache{
+		attachments: map[string]string{},
+	}
+}
+
+func (c *memoryUploadCache) Get(hash string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var attachment, ok = c.attachments[hash]
+
+	return attachment, ok
+}
+
+func (c *memoryUploadCache) Set(hash string, attachment string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.attachments[hash] = attachment
+}
+
+// NewUploader creates uploader, nil cache makes it upload every file
+func NewUploader(api *Api, cache UploadCache) *Uploader {
+	return &Uploader{
+		api:   api,
+		cache: cache,
+	}
+}
+
+// UploadPhoto uploads the photo for messages to the peer and returns the attachment like photo-1_2
+func (u *Uploader) UploadPhoto(ctx context.Context, peerId int, name string, file io.Reader) (string, error) {
+	return u.upload(ctx, uploadKindPhoto, name, file, func(content []byte) (string, error) {
+		var (
+			server = &uploadServer{}
+			upload = &photoUpload{}
+			saved  []savedMedia
+			err    error
+		)
+
+		if err = u.api.CallInto(ctx, MethodApiPhotosGetMessagesUploadServer, url.Values{`peer_id`: {strconv.Itoa(peerId)}}, server); err != nil {
+			return ``, err
+		}
+
+		if err = u.post(ctx, server.UploadUrl, `photo`, name, content, upload); err != nil {
+			return ``, err
+		}
+
+		if upload.Photo == `` || upload.Photo == `[]` {
+			return ``, errors.NewUploadFailedError(fmt.Sprintf(`photo "%s" is not accepted by the upload server`, name))
+		}
+
+		if err = u.api.CallInto(ctx, MethodApiPhotosSaveMessagesPhoto, url.Values{
+			`server`: {strconv.Itoa(upload.Server)},
+			`photo`:  {upload.Photo},
+			`hash`:   {upload.Hash},
+		}, &saved); err != nil {
+			return ``, err
+		}
+
+		if len(saved) == 0 {
+			return ``, errors.NewUploadFailedError(fmt.Sprintf(`photo "%s" is not saved`, name))
+		}
+
+		return saved[0].attachment(uploadKindPhoto), nil
+	})
+}
+
+// UploadDoc uploads the document for messages to the peer and returns the attachment like doc-1_2
+func (u *Uploader) UploadDoc(ctx context.Context, peerId int, name string, file io.Reader) (string, error) {
+	return u.uploadDoc(ctx, DocTypeDoc, peerId, name, file)
+}
+
+// UploadAudioMessage uploads the voice message in OGG or MP3 and returns the attachment like doc-1_2
+func (u *Uploader) UploadAudioMessage(ctx context.Context, peerId int, name string, file io.Reader) (string, error) {
+	return u.uploadDoc(ctx, DocTypeAudioMessage, peerId, name, file)
+}
+
+func (u *Uploader) uploadDoc(ctx context.Context, docType string, peerId int, name string, file io.Reader) (string, error) {
+	return u.upload(ctx, docType, name, file, func(content []byte) (string, error) {
+		var (
+			server = &uploadServer{}
+			upload = &docUpload{}
+			saved  = &savedDoc{}
+			media  *savedMedia
+			err    error
+		)
+
+		if err = u.api.CallInto(ctx, MethodApiDocsGetMessagesUploadServer, url.Values{
+			`type`:    {docType},
+			`peer_id`: {strconv.Itoa(peerId)},
+		}, server); err != nil {
+			return ``, err
+		}
+
+		if err = u.post(ctx, server.UploadUrl, `file`, name, content, upload); err != nil {
+			return ``, err
+		}
+
+		if upload.File == `` {
+			return ``, errors.NewUploadFailedError(fmt.Sprintf(`%s "%s" is not accepted by the upload server: %s`, docType, name, upload.Error))
+		}
+
+		if err = u.api.CallInto(ctx, MethodApiDocsSave, url.Values{`file`: {upload.File}, `title`: {name}}, saved); err != nil {
+			return ``, err
+		}
+
+		if media = saved.Doc; docType == DocTypeAudioMessage {
+			media = saved.AudioMessage
+		}
+
+		if media == nil {
+			return ``, errors.NewUploadFailedError(fmt.Sprintf(`%s "%s" is not saved`, docType, name))
+		}
+
+		return media.attachment(DocTypeDoc), nil
+	})
+}
+
+// upload reads the file and uploads it unless the cache knows the attachment of the same content
+func (u *Uploader) upload(ctx context.Context, kind string, name string, file io.Reader, upload func([]byte) (string, error)) (string, error) {
+	var (
+		content    []byte
+		hash       string
+		attachment string
+		ok         bool
+		err        error
+	)
+
+	if content, err = ioutil.ReadAll(file); err != nil {
+		return ``, err
+	}
+
+	hash = contentHash(kind, content)
+
+	if u.cache != nil {
+		if attachment, ok = u.cache.Get(hash); ok {
+			return attachment, nil
+		}
+	}
+
+	if attachment, err = upload(content); err != nil {
+		u.
+			api.
+			logger.
+			With(
+				zap.String(`kind`, kind),
+				zap.String(`name`, name),
+				zap.Error(err),
+			).
+			Error(`upload error`)
+
+		return ``, err
+	}
+
+	if u.cache != nil {
+		u.cache.Set(hash, attachment)
+	}
+
+	return attachment, nil
+}
+
+// post sends the file to the upload server as multipart form and decodes the answer into out
+func (u *Uploader) post(ctx context.Context, uploadUrl string, field string, name string, content []byte, out interface{}) error {
+	var (
+		body     = &bytes.Buffer{}
+		form     = multipart.NewWriter(body)
+		part     io.Writer
+		request  *http.Request
+		response *http.Response
+		err      error
+	)
+
+	if part, err = form.CreateFormFile(field, name); err != nil {
+		return err
+	}
+
+	if _, err = part.Write(content); err != nil {
+		return err
+	}
+
+	if err = form.Close(); err != nil {
+		return err
+	}
+
+	if request, err = http.NewRequestWithContext(ctx, `POST`, uploadUrl, body); err != nil {
+		return err
+	}
+
+	request.Header.Set(`Content-Type`, form.FormDataContentType())
+
+	if response, err = u.api.client.Do(request); err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusInternalServerError {
+		return errors.NewApiUnavailableError(fmt.Sprintf(`upload server answered %d`, response.StatusCode))
+	}
+
+	return json.NewDecoder(response.Body).Decode(out)
+}
+
+func (m savedMedia) attachment(kind string) string {
+	if m.AccessKey != `` {
+		return fmt.Sprintf(`%s%d_%d_%s`, kind, m.OwnerId, m.Id, m.AccessKey)
+	}
+
+	return fmt.Sprintf(`%s%d_%d`, kind, m.OwnerId, m.Id)
+}
+
+func contentHash(kind string, content []byte) string {
+	var sum = sha256.Sum256(content)
+
+	return kind + `:` + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"github.com/sepuka/vkbotserver/api/mocks"
+	"github.com/sepuka/vkbotserver/config"
+	"github.com/sepuka/vkbotserver/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func requestPath(path string) interface{} {
+	return mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == path
+	})
+}
+
+func uploadedFile(field string, content string) interface{} {
+	return mock.MatchedBy(func(req *http.Request) bool {
+		if req.URL.Host != `pu.vk.com` {
+			return false
+		}
+
+		var (
+			body, _ = req.GetBody()
+			data, _ = ioutil.ReadAll(body)
+			copied  = &http.Request{Header: req.Header, Body: ioutil.NopCloser(bytes.NewReader(data))}
+		)
+
+		if err := copied.ParseMultipartForm(1 << 20); err != nil {
+			return false
+		}
+
+		file, _, err := copied.FormFile(field)
+		if err != nil {
+			return false
+		}
+
+		uploaded, _ := ioutil.ReadAll(file)
+
+		return string(uploaded) == content
+	})
+}
+
+func TestUploader_UploadPhoto(t *testing.T) {
+	var (
+		client   = &mocks.HTTPClient{}
+		vk       = NewApi(zap.NewNop().Sugar(), config.Config{Api: config.Api{Token: `group_token`}}, client, &mocks.Rnder{})
+		uploader = NewUploader(vk, NewMemoryUploadCache())
+		ctx      = context.Background()
+	)
+
+	client.On(`Do`, requestPath(`/method/photos.getMessagesUploadServer`)).Once().
+		Return(answer(http.StatusOK, `{"response":{"album_id":-64,"upload_url":"https://pu.vk.com/c1/upload.php","user_id":0,"group_id":1}}`), nil)
+	client.On(`Do`, uploadedFile(`photo`, `jpeg content`)).Once().
+		Return(answer(http.StatusOK, `{"server":123,"photo":"[{\"photo\":\"a\"}]","hash":"abc"}`), nil)
+	client.On(`Do`, requestPath(`/method/photos.saveMessagesPhoto`)).Once().
+		Return(answer(http.StatusOK, `{"response":[{"album_id":-64,"date":1653041018,"id":457239017,"owner_id":-1,"access_key":"f00","sizes":[]}]}`), nil)
+
+	attachment, err := uploader.UploadPhoto(ctx, 557404793, `cat.jpg`, strings.NewReader(`jpeg content`))
+	assert.Nil(t, err)
+	assert.Equal(t, `photo-1_457239017_f00`, attachment)
+
+	attachment, err = uploader.UploadPhoto(ctx, 557404793, `same_cat.jpg`, strings.NewReader(`jpeg content`))
+	assert.Nil(t, err)
+	assert.Equal(t, `photo-1_457239017_f00`, attachment, `identical content is taken from the cache`)
+
+	client.AssertExpectations(t)
+}
+
+func TestUploader_UploadDoc(t *testing.T) {
+	var (
+		tests = map[string]struct {
+			upload   func(*Uploader) (string, error)
+			saved    string
+			expected string
+		}{
+			`doc`: {
+				upload: func(u *Uploader) (string, error) {
+					return u.UploadDoc(context.Background(), 557404793, `report.pdf`, strings.NewReader(`file content`))
+				},
+				saved:    `{"response":{"type":"doc","doc":{"id":6,"owner_id":-1,"title":"report.pdf","ext":"pdf"}}}`,
+				expected: `doc-1_6`,
+			},
+			`audio message`: {
+				upload: func(u *Uploader) (string, error) {
+					return u.UploadAudioMessage(context.Background(), 557404793, `voice.ogg`, strings.NewReader(`file content`))
+				},
+				saved:    `{"response":{"type":"audio_message","audio_message":{"id":7,"owner_id":-1,"duration":3,"access_key":"k"}}}`,
+				expected: `doc-1_7_k`,
+			},
+		}
+	)
+
+	for testName, testCase := range tests {
+		var (
+			client = &mocks.HTTPClient{}
+			vk     = NewApi(zap.NewNop().Sugar(), config.Config{}, client, &mocks.Rnder{})
+		)
+
+		client.On(`Do`, requestPath(`/method/docs.getMessagesUploadServer`)).Once().
+			Return(answer(http.StatusOK, `{"response":{"upload_url":"https://pu.vk.com/c2/upload.php"}}`), nil)
+		client.On(`Do`, uploadedFile(`file`, `file content`)).Once().
+			Return(answer(http.StatusOK, `{"file":"encoded_file"}`), nil)
+		client.On(`Do`, requestPath(`/method/docs.save`)).Once().
+			Return(answer(http.StatusOK, testCase.saved), nil)
+
+		attachment, err := testCase.upload(NewUploader(vk, nil))
+
+		assert.Nil(t, err, testName)
+		assert.Equal(t, testCase.expected, attachment, testName)
+		client.AssertExpectations(t)
+	}
+}
+
+func TestUploader_UploadDocRejected(t *testing.T) {
+	var (
+		client = &mocks.HTTPClient{}
+		vk     = NewApi(zap.NewNop().Sugar(), config.Config{}, client, &mocks.Rnder{})
+	)
+
+	client.On(`Do`, requestPath(`/method/docs.getMessagesUploadServer`)).Once().
+		Return(answer(http.StatusOK, `{"response":{"upload_url":"https://pu.vk.com/c2/upload.php"}}`), nil)
+	client.On(`Do`, uploadedFile(`file`, `virus`)).Once().
+		Return(answer(http.StatusOK, `{"error":"file_format_not_allowed"}`), nil)
+
+	_, err := NewUploader(vk, nil).UploadDoc(context.Background(), 557404793, `virus.exe`, strings.NewReader(`virus`))
+
+	assert.ErrorIs(t, err, errors.UploadFailed)
+}
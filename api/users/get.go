@@ -1,21 +1,20 @@
 package users
 
 import (
-	"fmt"
-	"github.com/mailru/easyjson"
+	"context"
 	"github.com/sepuka/vkbotserver/api"
+	"github.com/sepuka/vkbotserver/config"
 	"github.com/sepuka/vkbotserver/domain"
 	"go.uber.org/zap"
-	"net/http"
-	"net/http/httputil"
+	"net/url"
 )
 
 const (
-	apiPathTmpl = `%s/users.get?access_token=%s&v=%s`
+	methodUsersGet = `users.get`
 )
 
 type Get struct {
-	client   api.HTTPClient
+	api      *api.Api
 	logger   *zap.SugaredLogger
 	userRepo domain.UserRepository
 }
@@ -24,99 +23,68 @@ func NewGet(
 	client api.HTTPClient,
 	logger *zap.SugaredLogger,
 	userRepo domain.UserRepository,
+) *Get {
+	return NewGetApi(api.NewApi(logger, config.Config{}, client, api.NewRnder()), logger, userRepo)
+}
+
+// NewGetApi creates Get calling VK by the given Api, e.g. to share its rate limiter
+func NewGetApi(
+	vk *api.Api,
+	logger *zap.SugaredLogger,
+	userRepo domain.UserRepository,
 ) *Get {
 	return &Get{
-		client:   client,
+		api:      vk,
 		logger:   logger,
 		userRepo: userRepo,
 	}
 }
 
+// FillUser fills user's names by VK profile
 func (o *Get) FillUser(user *domain.User) {
+	o.FillUserContext(context.Background(), user)
+}
+
+// FillUserContext is the context-aware variant of the FillUser, users without a token are skipped
+func (o *Get) FillUserContext(ctx context.Context, user *domain.User) {
 	var (
-		err         error
-		path        = fmt.Sprintf(apiPathTmpl, api.Endpoint, user.Token, api.Version)
-		response    *http.Response
-		request     *http.Request
-		dump        []byte
-		apiResponse = &domain.ApiResponse{}
-		apiUser     *domain.VkUser
+		err      error
+		params   = url.Values{`access_token`: {user.Token}}
+		apiUsers []domain.VkUser
+		apiUser  *domain.VkUser
 	)
 
-	if request, err = http.NewRequest(`GET`, path, nil); err != nil {
+	if user.Token == `` {
 		o.
 			logger.
-			With(
-				zap.Error(err),
-				zap.String(`url`, path),
-				zap.String(`api`, `users.get`),
-			).
-			Error(`Build API request error`)
+			With(zap.String(`api`, methodUsersGet)).
+			Error(`User has no token`)
 
 		return
 	}
 
-	if response, err = o.client.Do(request); err != nil {
+	if err = o.api.CallInto(ctx, methodUsersGet, params, &apiUsers); err != nil {
 		o.
 			logger.
 			With(
 				zap.Error(err),
-				zap.String(`url`, path),
-				zap.String(`api`, `users.get`),
+				zap.String(`api`, methodUsersGet),
 			).
-			Error(`Send API request error`)
+			Error(`Users API request error`)
 
 		return
 	}
 
-	if dump, err = httputil.DumpResponse(response, true); err != nil {
+	if len(apiUsers) == 0 {
 		o.
 			logger.
-			With(
-				zap.Error(err),
-				zap.Int64(`size`, response.ContentLength),
-				zap.Int(`code`, response.StatusCode),
-				zap.String(`api`, `users.get`),
-			).
-			Error(`Dump response error`)
-
-		return
-	}
-
-	o.
-		logger.
-		With(
-			zap.String(`api`, `users.get`),
-			zap.ByteString(`response`, dump),
-		).
-		Info(`VK API response`)
-
-	if err = easyjson.UnmarshalFromReader(response.Body, apiResponse); err != nil {
-		o.
-			logger.
-			With(
-				zap.Error(err),
-				zap.String(`api`, `users.get`),
-			).
-			Error(`Unmarshalling oauth response error`)
-
-		return
-	}
-
-	if apiResponse.Error.ErrorCode > 0 {
-		o.
-			logger.
-			With(
-				zap.String(`message`, apiResponse.Error.ErrorMessage),
-				zap.Int(`code`, apiResponse.Error.ErrorCode),
-				zap.String(`api`, `users.get`),
-			).
-			Error(`Response has an error`)
+			With(zap.String(`api`, methodUsersGet)).
+			Error(`Response has no users`)
 
 		return
 	}
 
-	apiUser = &apiResponse.Response[0]
+	apiUser = &apiUsers[0]
 	user.FirstName = apiUser.FirstName
 	user.LastName = apiUser.LastName
 
@@ -125,7 +93,7 @@ func (o *Get) FillUser(user *domain.User) {
 			logger.
 			With(
 				zap.Error(err),
-				zap.String(`api`, `users.get`),
+				zap.String(`api`, methodUsersGet),
 			).
 			Error(`Update user info error`)
 	}
@@ -2,46 +2,52 @@ package users
 
 import (
 	"bytes"
-	"fmt"
 	"github.com/sepuka/vkbotserver/api"
 	"github.com/sepuka/vkbotserver/api/mocks"
+	"github.com/sepuka/vkbotserver/config"
 	"github.com/sepuka/vkbotserver/domain"
 	mocks2 "github.com/sepuka/vkbotserver/domain/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"testing"
 )
 
-func TestVkAuth_Exec_FillUser_FailedWithoutToken(t *testing.T) {
-	const (
-		responseUsersGet = `{"error":{"error_code":5,"error_msg":"User authorization failed: no access_token passed.","request_params":[{"key":"api_token","value":"c991fd1144d1de516fab2cb6d671ebe16a785f44ca281304414dbf0157fe0b5c0b2973d58bbc40886e1a5"},{"key":"v","value":"5.170"},{"key":"method","value":"users.get"},{"key":"oauth","value":"1"}]}}`
-	)
+func usersGetRequest(token string) interface{} {
+	return mock.MatchedBy(func(req *http.Request) bool {
+		var (
+			body, _   = req.GetBody()
+			data, _   = ioutil.ReadAll(body)
+			params, _ = url.ParseQuery(string(data))
+		)
 
+		return req.URL.Path == `/method/users.get` &&
+			params.Get(`access_token`) == token &&
+			params.Get(`v`) == api.Version
+	})
+}
+
+func TestVkAuth_Exec_FillUser_FailedWithoutToken(t *testing.T) {
 	var (
-		expectedOutcomeReq          = &http.Request{}
-		expectedIncomeResp          = &http.Response{}
 		logger                      = zap.NewNop().Sugar()
 		userRepo                    = mocks2.UserRepository{}
 		client                      = mocks.HTTPClient{}
-		tokenUrl                    string
 		someExistsUserWithEmptyName = &domain.User{}
 	)
 
-	expectedIncomeResp = &http.Response{
-		Body: ioutil.NopCloser(bytes.NewReader([]byte(responseUsersGet))),
-	}
-
-	tokenUrl = fmt.Sprintf(apiPathTmpl, api.Endpoint, someExistsUserWithEmptyName.Token, api.Version)
-	expectedOutcomeReq, _ = http.NewRequest(`GET`, tokenUrl, nil)
-	client.On(`Do`, expectedOutcomeReq).Once().Return(expectedIncomeResp, nil)
+	// Do not call VK with the group token instead of the user's one
+	client.On(`Do`, mock.Anything).Times(0)
 	// Do not update user`s props because an error was occurred
 	userRepo.On(`Update`, someExistsUserWithEmptyName).Times(0)
 
 	userGetter := NewGet(&client, logger, &userRepo)
 
 	userGetter.FillUser(someExistsUserWithEmptyName)
+	client.AssertNotCalled(t, `Do`, mock.Anything)
+	userRepo.AssertNotCalled(t, `Update`, someExistsUserWithEmptyName)
 }
 
 func TestVkAuth_Exec_FillUser_Update(t *testing.T) {
@@ -50,26 +56,23 @@ func TestVkAuth_Exec_FillUser_Update(t *testing.T) {
 	)
 
 	var (
-		expectedOutcomeReq = &http.Request{}
 		expectedIncomeResp = &http.Response{}
 		logger             = zap.NewNop().Sugar()
 		userRepo           = mocks2.UserRepository{}
 		client             = mocks.HTTPClient{}
-		tokenUrl           string
-		someUser           = &domain.User{}
+		cfg                = config.Config{Api: config.Api{Token: `group_token`}}
+		someUser           = &domain.User{Token: `user_token`}
 	)
 
 	expectedIncomeResp = &http.Response{
 		Body: ioutil.NopCloser(bytes.NewReader([]byte(responseUsersGet))),
 	}
 
-	tokenUrl = fmt.Sprintf(apiPathTmpl, api.Endpoint, someUser.Token, api.Version)
-	expectedOutcomeReq, _ = http.NewRequest(`GET`, tokenUrl, nil)
-	client.On(`Do`, expectedOutcomeReq).Once().Return(expectedIncomeResp, nil)
+	client.On(`Do`, usersGetRequest(someUser.Token)).Once().Return(expectedIncomeResp, nil)
 	// Do not update user`s props because an error was occurred
 	userRepo.On(`Update`, someUser).Once().Return(nil)
 
-	userGetter := NewGet(&client, logger, &userRepo)
+	userGetter := NewGetApi(api.NewApi(logger, cfg, &client, api.NewRnder()), logger, &userRepo)
 
 	userGetter.FillUser(someUser)
 	assert.Equal(t, `Шломин`, someUser.LastName)
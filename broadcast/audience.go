@@ -0,0 +1,37 @@
+package broadcast
+
+import "context"
+
+type (
+	// Audience returns recipients of the broadcast page by page. The order must be stable,
+	// because the broadcast is resumed from the offset of the first unsent page
+	Audience interface {
+		Peers(ctx context.Context, offset int, limit int) ([]int, error)
+	}
+
+	// AudienceFunc adapts a function, e.g. a repository query, to the Audience
+	AudienceFunc func(ctx context.Context, offset int, limit int) ([]int, error)
+
+	listAudience []int
+)
+
+func (f AudienceFunc) Peers(ctx context.Context, offset int, limit int) ([]int, error) {
+	return f(ctx, offset, limit)
+}
+
+// NewListAudience creates audience of the explicit peer list
+func NewListAudience(peerIds ...int) Audience {
+	return listAudience(peerIds)
+}
+
+func (a listAudience) Peers(ctx context.Context, offset int, limit int) ([]int, error) {
+	if offset >= len(a) {
+		return nil, nil
+	}
+
+	if offset+limit > len(a) {
+		return a[offset:], nil
+	}
+
+	return a[offset : offset+limit], nil
+}
@@ -0,0 +1,377 @@
+package broadcast
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/sepuka/vkbotserver/api"
+	"github.com/sepuka/vkbotserver/api/button"
+	"github.com/sepuka/vkbotserver/errors"
+	"go.uber.org/zap"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	StateRunning   State = `running`
+	StatePaused    State = `paused`
+	StateCancelled State = `cancelled`
+	StateDone      State = `done`
+
+	OutcomeSent    Outcome = `sent`
+	OutcomeBlocked Outcome = `blocked`
+	OutcomeFailed  Outcome = `failed`
+
+	defaultRetryDelay  = time.Second
+	defaultMaxAttempts = 10
+)
+
+type (
+	State   string
+	Outcome string
+
+	// Message is sent to every recipient of the broadcast, Keyboard is optional
+	Message struct {
+		Text       string
+		Attachment string
+		Keyboard   *button.Keyboard
+	}
+
+	// Result is the outcome of the broadcast for the recipient, Error is set unless the message is sent
+	Result struct {
+		PeerId  int            `json:"peer_id"`
+		Outcome Outcome        `json:"outcome"`
+		Message api.MessageRef `json:"message"`
+		Error   string         `json:"error,omitempty"`
+	}
+
+	// Progress of the broadcast, Offset is the number of the audience peers already processed
+	Progress struct {
+		Id        string    `json:"id"`
+		State     State     `json:"state"`
+		Offset    int       `json:"offset"`
+		Sent      int       `json:"sent"`
+		Blocked   int       `json:"blocked"`
+		Failed    int       `json:"failed"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+
+	// Store keeps progress and results of broadcasts, Load returns nil without error if there is no broadcast.
+	// Save must store the progress and the results of the page together
+	Store interface {
+		Load(ctx context.Context, id string) (*Progress, error)
+		Save(ctx context.Context, progress *Progress, results []Result) error
+		Results(ctx context.Context, id string) ([]Result, error)
+	}
+
+	// Engine sends broadcasts by messages.send with up to 100 peer_ids per call,
+	// so the broadcast takes one slot of the API rate limit per page
+	Engine struct {
+		api         *api.Api
+		logger      *zap.SugaredLogger
+		store       Store
+		now         func() time.Time
+		retryDelay  time.Duration
+		maxAttempts int
+	}
+
+	// Broadcast is the running broadcast which may be paused, resumed or cancelled.
+	// mu guards the progress, saveMu orders its saves, so Progress isn't blocked by the store
+	Broadcast struct {
+		engine   *Engine
+		audience Audience
+		message  api.OutcomeMessage
+		mu       sync.Mutex
+		saveMu   sync.Mutex
+		progress Progress
+		signal   chan struct{}
+		done     chan struct{}
+		err      error
+	}
+)
+
+// NewEngine creates engine which keeps progress of broadcasts in the store
+func NewEngine(vk *api.Api, logger *zap.SugaredLogger, store Store) *Engine {
+	return &Engine{
+		api:         vk,
+		logger:      logger,
+		store:       store,
+		now:         time.Now,
+		retryDelay:  defaultRetryDelay,
+		maxAttempts: defaultMaxAttempts,
+	}
+}
+
+// Start starts the broadcast or resumes the stored one with the same id from the first unsent page.
+// A paused broadcast stays paused until Resume, a finished one is not sent again.
+// The broadcast stops without changing its state when ctx is done or a page fails, so it may be resumed after a restart
+func (e *Engine) Start(ctx context.Context, id string, audience Audience, msg Message) (*Broadcast, error) {
+	var (
+		broadcast = &Broadcast{
+			engine:   e,
+			audience: audience,
+			message:  api.OutcomeMessage{Message: msg.Text, Attachment: msg.Attachment},
+			signal:   make(chan struct{}, 1),
+			done:     make(chan struct{}),
+		}
+		stored *Progress
+		js     []byte
+		err    error
+	)
+
+	if msg.Keyboard != nil {
+		if js, err = json.Marshal(msg.Keyboard); err != nil {
+			return nil, err
+		}
+
+		broadcast.message.Keyboard = string(js)
+	}
+
+	if stored, err = e.store.Load(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if stored == nil {
+		stored = &Progress{Id: id, State: StateRunning}
+		if err = e.save(ctx, stored, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	broadcast.progress = *stored
+
+	go broadcast.run(ctx)
+
+	return broadcast, nil
+}
+
+// Progress returns the current progress of the broadcast
+func (b *Broadcast) Progress() Progress {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.progress
+}
+
+// Wait waits until the broadcast is done, cancelled or stopped by an error or ctx
+func (b *Broadcast) Wait() (Progress, error) {
+	<-b.done
+
+	return b.Progress(), b.err
+}
+
+// Pause stops sending after the current page
+func (b *Broadcast) Pause() error {
+	return b.control(StatePaused)
+}
+
+// Resume continues sending of the paused broadcast
+func (b *Broadcast) Resume() error {
+	return b.control(StateRunning)
+}
+
+// Cancel stops sending after the current page, the cancelled broadcast can't be resumed
+func (b *Broadcast) Cancel() error {
+	return b.control(StateCancelled)
+}
+
+func (b *Broadcast) control(state State) error {
+	var err = b.update(context.Background(), nil, func(progress *Progress) (bool, error) {
+		if progress.State == StateDone || progress.State == StateCancelled {
+			return false, errors.NewBroadcastFinishedError(fmt.Sprintf(`broadcast "%s" is %s`, progress.Id, progress.State))
+		}
+
+		progress.State = state
+
+		return true, nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	select {
+	case b.signal <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+func (b *Broadcast) run(ctx context.Context) {
+	var (
+		peers   []int
+		results []Result
+		running bool
+		err     error
+	)
+
+	defer close(b.done)
+
+	for {
+		if running, err = b.await(ctx); !running {
+			break
+		}
+
+		if peers, err = b.audience.Peers(ctx, b.Progress().Offset, api.MaxPeerIds); err != nil {
+			break
+		}
+
+		if len(peers) == 0 {
+			err = b.finish(ctx)
+			break
+		}
+
+		if results, err = b.send(ctx, peers); err != nil {
+			break
+		}
+
+		if err = b.record(ctx, len(peers), results); err != nil {
+			break
+		}
+	}
+
+	if err != nil {
+		b.
+			engine.
+			logger.
+			With(
+				zap.String(`broadcast`, b.Progress().Id),
+				zap.Error(err),
+			).
+			Error(`broadcast is stopped`)
+	}
+
+	b.err = err
+}
+
+// await waits while the broadcast is paused and returns false if it's finished or ctx is done
+func (b *Broadcast) await(ctx context.Context) (bool, error) {
+	for {
+		switch b.Progress().State {
+		case StateRunning:
+			return true, nil
+		case StatePaused:
+			select {
+			case <-b.signal:
+			case <-ctx.Done():
+				return false, ctx.Err()
+			}
+		default:
+			return false, nil
+		}
+	}
+}
+
+// send sends the page and retries it while the error is retryable up to maxAttempts times. The random_id depends
+// on the broadcast and the page, so VK drops duplicates of the page sent again after an error or a restart
+func (b *Broadcast) send(ctx context.Context, peers []int) ([]Result, error) {
+	var (
+		msg       = b.message
+		progress  = b.Progress()
+		responses []api.SendResult
+		results   = make([]Result, 0, len(peers))
+		err       error
+	)
+
+	msg.RandomId = api.StableRandomId(progress.Id, strconv.Itoa(progress.Offset))
+
+	for attempt := 1; ; attempt++ {
+		if responses, err = b.engine.api.SendMessageToPeersContext(ctx, peers, msg); err == nil {
+			break
+		}
+
+		if !errors.IsRetryable(err) || attempt >= b.engine.maxAttempts {
+			return nil, err
+		}
+
+		select {
+		case <-time.After(b.engine.retryDelay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	for _, response := range responses {
+		var result = Result{PeerId: response.PeerId, Outcome: OutcomeSent, Message: response.MessageRef}
+
+		if response.Error != nil {
+			result.Error = response.Error.Error()
+			if result.Outcome = OutcomeFailed; response.Error.Code == errors.ApiCodeCantSendToUser || response.Error.Code == errors.ApiCodeBlacklisted {
+				result.Outcome = OutcomeBlocked
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// record moves the broadcast to the next page and stores results of the current one
+func (b *Broadcast) record(ctx context.Context, processed int, results []Result) error {
+	return b.update(ctx, results, func(progress *Progress) (bool, error) {
+		progress.Offset += processed
+
+		for _, result := range results {
+			switch result.Outcome {
+			case OutcomeSent:
+				progress.Sent++
+			case OutcomeBlocked:
+				progress.Blocked++
+			default:
+				progress.Failed++
+			}
+		}
+
+		return true, nil
+	})
+}
+
+func (b *Broadcast) finish(ctx context.Context) error {
+	return b.update(ctx, nil, func(progress *Progress) (bool, error) {
+		if progress.State != StateRunning {
+			return false, nil
+		}
+
+		progress.State = StateDone
+
+		return true, nil
+	})
+}
+
+// update changes the copy of the progress by fn and saves it if fn says so, the progress is replaced after the save.
+// Updates are serialized by saveMu, b.mu isn't held during the store round trip
+func (b *Broadcast) update(ctx context.Context, results []Result, fn func(progress *Progress) (bool, error)) error {
+	var (
+		progress Progress
+		changed  bool
+		err      error
+	)
+
+	b.saveMu.Lock()
+	defer b.saveMu.Unlock()
+
+	progress = b.Progress()
+
+	if changed, err = fn(&progress); !changed || err != nil {
+		return err
+	}
+
+	if err = b.engine.save(ctx, &progress, results); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.progress = progress
+	b.mu.Unlock()
+
+	return nil
+}
+
+func (e *Engine) save(ctx context.Context, progress *Progress, results []Result) error {
+	progress.UpdatedAt = e.now()
+
+	return e.store.Save(ctx, progress, results)
+}
@@ -0,0 +1,306 @@
+package broadcast
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/sepuka/vkbotserver/api"
+	"github.com/sepuka/vkbotserver/api/apitest"
+	"github.com/sepuka/vkbotserver/api/mocks"
+	"github.com/sepuka/vkbotserver/config"
+	"github.com/sepuka/vkbotserver/errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// vkServer answers messages.send with a result for each peer, failures map peers to error codes
+func vkServer(failures map[int]int) *apitest.Server {
+	return apitest.NewServer(func(form url.Values) string {
+		var (
+			peers   = strings.Split(form.Get(`peer_ids`), `,`)
+			results = make([]map[string]interface{}, 0, len(peers))
+		)
+
+		for i, peer := range peers {
+			var peerId, _ = strconv.Atoi(peer)
+
+			if code, ok := failures[peerId]; ok {
+				results = append(results, map[string]interface{}{`peer_id`: peerId, `error`: map[string]interface{}{`error_code`: code, `error_msg`: `failure`}})
+			} else {
+				results = append(results, map[string]interface{}{`peer_id`: peerId, `message_id`: i + 1})
+			}
+		}
+
+		js, _ := json.Marshal(map[string]interface{}{`response`: results})
+
+		return string(js)
+	})
+}
+
+// pages returns peers of the sent pages
+func pages(server *apitest.Server) [][]string {
+	var pages [][]string
+
+	for _, form := range server.Forms() {
+		pages = append(pages, strings.Split(form.Get(`peer_ids`), `,`))
+	}
+
+	return pages
+}
+
+func newEngine(server *apitest.Server, store Store) *Engine {
+	var vk = api.NewApi(zap.NewNop().Sugar(), config.Config{}, server, &mocks.Rnder{})
+
+	return NewEngine(vk, zap.NewNop().Sugar(), store)
+}
+
+func peers(from int, count int) []int {
+	var ids = make([]int, 0, count)
+
+	for i := 0; i < count; i++ {
+		ids = append(ids, from+i)
+	}
+
+	return ids
+}
+
+func TestEngine_Start(t *testing.T) {
+	var (
+		server = vkServer(map[int]int{
+			3:   errors.ApiCodeCantSendToUser,
+			4:   errors.ApiCodeBlacklisted,
+			120: errors.ApiCodeAccessDenied,
+		})
+		store  = NewMemoryStore()
+		engine = newEngine(server, store)
+	)
+
+	broadcast, err := engine.Start(context.Background(), `news`, NewListAudience(peers(1, 150)...), Message{Text: `hello`})
+	assert.Nil(t, err)
+
+	progress, err := broadcast.Wait()
+	assert.Nil(t, err)
+	assert.Equal(t, StateDone, progress.State)
+	assert.Equal(t, 150, progress.Offset)
+	assert.Equal(t, 147, progress.Sent)
+	assert.Equal(t, 2, progress.Blocked)
+	assert.Equal(t, 1, progress.Failed)
+
+	assert.Len(t, pages(server), 2)
+	assert.Len(t, pages(server)[0], api.MaxPeerIds)
+	assert.Len(t, pages(server)[1], 50)
+	assert.NotEqual(t, server.Forms()[0].Get(`random_id`), server.Forms()[1].Get(`random_id`), `each page has its own random_id`)
+
+	results, err := store.Results(context.Background(), `news`)
+	assert.Nil(t, err)
+	assert.Len(t, results, 150)
+	assert.Equal(t, Result{PeerId: 1, Outcome: OutcomeSent, Message: api.MessageRef{PeerId: 1, MessageId: 1}}, results[0])
+	assert.Equal(t, OutcomeBlocked, results[2].Outcome)
+	assert.Equal(t, OutcomeBlocked, results[3].Outcome)
+	assert.Equal(t, OutcomeFailed, results[119].Outcome)
+	assert.NotEmpty(t, results[119].Error)
+
+	stored, _ := store.Load(context.Background(), `news`)
+	assert.Equal(t, progress, *stored)
+}
+
+func TestEngine_StartResumes(t *testing.T) {
+	var (
+		tests = map[string]struct {
+			stored   Progress
+			pages    int
+			sent     int
+			expected State
+		}{
+			`interrupted`: {
+				stored:   Progress{Id: `news`, State: StateRunning, Offset: 100, Sent: 100},
+				pages:    1,
+				sent:     150,
+				expected: StateDone,
+			},
+			`done`: {
+				stored:   Progress{Id: `news`, State: StateDone, Offset: 150, Sent: 150},
+				sent:     150,
+				expected: StateDone,
+			},
+			`cancelled`: {
+				stored:   Progress{Id: `news`, State: StateCancelled, Offset: 100, Sent: 100},
+				sent:     100,
+				expected: StateCancelled,
+			},
+		}
+	)
+
+	for testName, testCase := range tests {
+		var (
+			server = vkServer(nil)
+			store  = NewMemoryStore()
+			engine = newEngine(server, store)
+		)
+
+		_ = store.Save(context.Background(), &testCase.stored, nil)
+
+		broadcast, err := engine.Start(context.Background(), `news`, NewListAudience(peers(1, 150)...), Message{Text: `hello`})
+		assert.Nil(t, err, testName)
+
+		progress, err := broadcast.Wait()
+		assert.Nil(t, err, testName)
+		assert.Equal(t, testCase.expected, progress.State, testName)
+		assert.Equal(t, testCase.sent, progress.Sent, testName)
+		assert.Len(t, pages(server), testCase.pages, testName)
+		if testCase.pages > 0 {
+			assert.Equal(t, `101`, pages(server)[0][0], testName)
+		}
+	}
+}
+
+func TestBroadcast_PauseResumeCancel(t *testing.T) {
+	var (
+		server = vkServer(nil)
+		store  = NewMemoryStore()
+		engine = newEngine(server, store)
+		ctx    = context.Background()
+	)
+
+	_ = store.Save(ctx, &Progress{Id: `news`, State: StatePaused}, nil)
+
+	broadcast, err := engine.Start(ctx, `news`, NewListAudience(peers(1, 10)...), Message{Text: `hello`})
+	assert.Nil(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+	assert.Empty(t, pages(server), `the paused broadcast waits for resume`)
+
+	assert.Nil(t, broadcast.Resume())
+
+	progress, err := broadcast.Wait()
+	assert.Nil(t, err)
+	assert.Equal(t, StateDone, progress.State)
+	assert.Equal(t, 10, progress.Sent)
+	assert.ErrorIs(t, broadcast.Pause(), errors.BroadcastFinished)
+
+	_ = store.Save(ctx, &Progress{Id: `digest`, State: StatePaused}, nil)
+
+	broadcast, _ = engine.Start(ctx, `digest`, NewListAudience(peers(1, 10)...), Message{Text: `hello`})
+	assert.Nil(t, broadcast.Cancel())
+
+	progress, err = broadcast.Wait()
+	assert.Nil(t, err)
+	assert.Equal(t, StateCancelled, progress.State)
+	assert.Len(t, pages(server), 1)
+
+	stored, _ := store.Load(ctx, `digest`)
+	assert.Equal(t, StateCancelled, stored.State)
+	assert.ErrorIs(t, broadcast.Resume(), errors.BroadcastFinished)
+}
+
+func TestBroadcast_RetriesPage(t *testing.T) {
+	var (
+		internal = `{"error":{"error_code":10,"error_msg":"Internal server error"}}`
+		sent     = `{"response":[{"peer_id":1,"message_id":1}]}`
+		server   = apitest.NewQueueServer(sent, internal)
+		engine   = newEngine(server, NewMemoryStore())
+	)
+
+	engine.retryDelay = time.Millisecond
+
+	broadcast, _ := engine.Start(context.Background(), `news`, NewListAudience(1), Message{Text: `hello`})
+	progress, err := broadcast.Wait()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, progress.Sent)
+	assert.Len(t, server.Forms(), 2)
+	assert.Equal(t, server.Forms()[0].Get(`random_id`), server.Forms()[1].Get(`random_id`), `the repeated page keeps its random_id`)
+}
+
+func TestBroadcast_StopsOnPermanentError(t *testing.T) {
+	var (
+		failed = `{"error":{"error_code":5,"error_msg":"User authorization failed"}}`
+		server = apitest.NewQueueServer(failed)
+		engine = newEngine(server, NewMemoryStore())
+	)
+
+	broadcast, _ := engine.Start(context.Background(), `news`, NewListAudience(1, 2), Message{Text: `hello`})
+	progress, err := broadcast.Wait()
+
+	assert.ErrorIs(t, err, errors.ApiAuthFailed)
+	assert.Equal(t, Progress{Id: `news`, State: StateRunning, UpdatedAt: progress.UpdatedAt}, progress, `the broadcast may be resumed`)
+	assert.Len(t, server.Forms(), 1)
+}
+
+func TestBroadcast_StopsAfterMaxAttempts(t *testing.T) {
+	var (
+		internal = `{"error":{"error_code":10,"error_msg":"Internal server error"}}`
+		server   = apitest.NewQueueServer(internal)
+		engine   = newEngine(server, NewMemoryStore())
+	)
+
+	engine.retryDelay = time.Millisecond
+	engine.maxAttempts = 3
+
+	broadcast, _ := engine.Start(context.Background(), `news`, NewListAudience(1), Message{Text: `hello`})
+	progress, err := broadcast.Wait()
+
+	assert.ErrorIs(t, err, errors.ApiInternal)
+	assert.Equal(t, StateRunning, progress.State, `the broadcast may be resumed`)
+	assert.Len(t, server.Forms(), 3)
+}
+
+// slowStore holds saves of page results until release is closed
+type slowStore struct {
+	Store
+	saving  chan struct{}
+	release chan struct{}
+}
+
+func (s *slowStore) Save(ctx context.Context, progress *Progress, results []Result) error {
+	if results != nil {
+		s.saving <- struct{}{}
+		<-s.release
+	}
+
+	return s.Store.Save(ctx, progress, results)
+}
+
+func TestBroadcast_ProgressWhileSaving(t *testing.T) {
+	var (
+		store    = &slowStore{Store: NewMemoryStore(), saving: make(chan struct{}), release: make(chan struct{})}
+		engine   = newEngine(vkServer(nil), store)
+		progress = make(chan Progress)
+	)
+
+	broadcast, _ := engine.Start(context.Background(), `news`, NewListAudience(1, 2), Message{Text: `hello`})
+	<-store.saving
+
+	go func() {
+		progress <- broadcast.Progress()
+	}()
+
+	select {
+	case current := <-progress:
+		assert.Equal(t, 0, current.Sent, `the page isn't recorded until it's saved`)
+	case <-time.After(time.Second):
+		t.Fatal(`Progress must not wait for the store`)
+	}
+
+	close(store.release)
+
+	final, err := broadcast.Wait()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, final.Sent)
+}
+
+func TestNewListAudience(t *testing.T) {
+	var audience = NewListAudience(1, 2, 3)
+
+	for offset, expected := range map[int][]int{0: {1, 2}, 2: {3}, 3: nil} {
+		peers, err := audience.Peers(context.Background(), offset, 2)
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, peers, fmt.Sprintf(`offset %d`, offset))
+	}
+}
@@ -0,0 +1,176 @@
+package broadcast
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+const (
+	progressKeyTmpl = `vkbot_server_broadcast_%s`
+	resultsKeyTmpl  = `vkbot_server_broadcast_%s_results`
+)
+
+type (
+	memoryStore struct {
+		mu       sync.Mutex
+		progress map[string]Progress
+		results  map[string]map[int]Result
+	}
+
+	redisStore struct {
+		client *redis.Client
+	}
+)
+
+// NewMemoryStore creates store which keeps broadcasts in the process memory, they aren't resumed after a restart
+func NewMemoryStore() *memoryStore {
+	return &memoryStore{
+		progress: map[string]Progress{},
+		results:  map[string]map[int]Result{},
+	}
+}
+
+func (s *memoryStore) Load(ctx context.Context, id string) (*Progress, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var progress, ok = s.progress[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &progress, nil
+}
+
+func (s *memoryStore) Save(ctx context.Context, progress *Progress, results []Result) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.progress[progress.Id] = *progress
+
+	if s.results[progress.Id] == nil {
+		s.results[progress.Id] = map[int]Result{}
+	}
+
+	for _, result := range results {
+		s.results[progress.Id][result.PeerId] = result
+	}
+
+	return nil
+}
+
+func (s *memoryStore) Results(ctx context.Context, id string) ([]Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var results = make([]Result, 0, len(s.results[id]))
+
+	for _, result := range s.results[id] {
+		results = append(results, result)
+	}
+
+	sortResults(results)
+
+	return results, nil
+}
+
+// NewRedisStore creates store which keeps progress as JSON and results as a hash by the peer id
+func NewRedisStore(client *redis.Client) *redisStore {
+	return &redisStore{
+		client: client,
+	}
+}
+
+func (s *redisStore) Load(ctx context.Context, id string) (*Progress, error) {
+	var (
+		progress = &Progress{}
+		data     []byte
+		err      error
+	)
+
+	if data, err = s.client.Get(ctx, fmt.Sprintf(progressKeyTmpl, id)).Bytes(); err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, progress); err != nil {
+		return nil, err
+	}
+
+	return progress, nil
+}
+
+func (s *redisStore) Save(ctx context.Context, progress *Progress, results []Result) error {
+	var (
+		fields = make([]interface{}, 0, len(results)*2)
+		data   []byte
+		err    error
+	)
+
+	if data, err = json.Marshal(progress); err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		var js []byte
+
+		if js, err = json.Marshal(result); err != nil {
+			return err
+		}
+
+		fields = append(fields, strconv.Itoa(result.PeerId), js)
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if len(fields) > 0 {
+			pipe.HSet(ctx, fmt.Sprintf(resultsKeyTmpl, progress.Id), fields...)
+		}
+		pipe.Set(ctx, fmt.Sprintf(progressKeyTmpl, progress.Id), data, 0)
+
+		return nil
+	})
+
+	return err
+}
+
+func (s *redisStore) Results(ctx context.Context, id string) ([]Result, error) {
+	var (
+		stored  map[string]string
+		results []Result
+		err     error
+	)
+
+	if stored, err = s.client.HGetAll(ctx, fmt.Sprintf(resultsKeyTmpl, id)).Result(); err != nil {
+		return nil, err
+	}
+
+	results = make([]Result, 0, len(stored))
+
+	for _, data := range stored {
+		var result Result
+
+		if err = json.Unmarshal([]byte(data), &result); err != nil {
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	sortResults(results)
+
+	return results, nil
+}
+
+func sortResults(results []Result) {
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].PeerId < results[j].PeerId
+	})
+}
@@ -6,14 +6,56 @@ import (
 	"time"
 )
 
+const (
+	NetworkUnix  = `unix`
+	NetworkTcp   = `tcp`
+	ProtocolFcgi = `fcgi`
+	ProtocolHttp = `http`
+)
+
 type (
 	// Logger writes to stdout
 	Logger struct {
 		Prod bool
 	}
 	// Api config
+	// RateLimit limits calls with the group token, UserRateLimit limits calls with each user token separately
 	Api struct {
-		Token string `default:"???_there_is_the_access_api_token"`
+		Token         string `default:"???_there_is_the_access_api_token"`
+		RateLimit     RateLimit
+		UserRateLimit RateLimit
+		Retry         Retry
+	}
+
+	// Retry repeats API calls failed by network errors, 5xx responses and VK codes 6, 9, 10
+	// MaxAttempts includes the first attempt, 1 disables retries
+	// Backoff is the delay before the second attempt, it doubles for every next attempt up to MaxBackoff,
+	// a random jitter up to a half of the delay is added. The call is not repeated if the delay exceeds the context deadline
+	Retry struct {
+		MaxAttempts int           `default:"3"`
+		Backoff     time.Duration `default:"200000000"`
+		MaxBackoff  time.Duration `default:"5000000000"`
+	}
+
+	// Outbox persists outgoing messages in Dir and delivers them in background, Interval is the period of polling the store
+	// A failed delivery is repeated after Backoff doubling for every next attempt up to MaxBackoff,
+	// the message is marked failed after MaxAttempts, 0 means it's repeated until VK accepts or rejects it
+	Outbox struct {
+		Dir         string        `default:"/var/lib/vkbotserver/outbox"`
+		Interval    time.Duration `default:"1000000000"`
+		MaxAttempts int           `default:"10"`
+		Backoff     time.Duration `default:"1000000000"`
+		MaxBackoff  time.Duration `default:"300000000000"`
+	}
+
+	// RateLimit is the token bucket of outgoing API calls, VK allows 20 calls per second for the group token
+	// and 3 calls per second for a user token. Zero Rate disables the limiter
+	// Burst is the bucket size, Rate rounded up by default
+	// Block makes the call wait for a free slot, otherwise the call fails immediately
+	RateLimit struct {
+		Rate  float64
+		Burst int
+		Block bool
 	}
 
 	// Cache requests
@@ -34,20 +76,64 @@ type (
 	YaOauth struct {
 		Path string
 	}
+
+	// Listen configures the way the socket server accepts connections
+	// Network is unix or tcp, Protocol is fcgi or http
+	// Address is used by tcp network only, unix socket path is taken from the Socket option
+	// TLS is enabled for http protocol when both CertFile and KeyFile are set
+	Listen struct {
+		Network  string `default:"unix"`
+		Protocol string `default:"fcgi"`
+		Address  string `default:":8080"`
+		CertFile string
+		KeyFile  string
+	}
+
+	// Async makes the server answer "ok" immediately and handle events by the worker pool
+	// Workers is the number of concurrent handlers, events of the same peer are handled in order by one worker
+	// QueueSize is the queue length of each worker
+	// Block makes the server wait for a free place in the queue, otherwise the event is rejected and VK retries it later
+	Async struct {
+		Enabled   bool
+		Workers   int `default:"4"`
+		QueueSize int `default:"100"`
+		Block     bool
+	}
+
+	// LongPoll configures Bots Long Poll API which may be used instead of the Callback API socket
+	// Wait in seconds, max is 90
+	LongPoll struct {
+		GroupId int
+		Wait    int `default:"25"`
+	}
 )
 
 // Config is the struct which is filling by config from App path like /etc/app.yml
 type Config struct {
 	Confirmation string
 	Socket       string `default:"/var/run/vkbotserver.sock"`
+	Listen       Listen
 	Logger       Logger
 	Api          Api
 	Cache        Cache
 	VkOauth      VkOauth
 	YaOauth      YaOauth
+	LongPoll     LongPoll
+	Async        Async
+	Outbox       Outbox
 	// if your web-server configured to handle VKbot-requests with some prefix
 	// like /mybot/ rewrite this opt
 	PathPrefix string `default:"/"`
+	// how long the server waits for running handlers on shutdown, 10s by default
+	ShutdownTimeout time.Duration `default:"10000000000"`
+	// Callback API secret keys by group id, events are not verified without keys,
+	// once any key is set events of groups without a key are rejected
+	Secrets map[int32]string
+}
+
+// IsTLS tells whether the server must serve HTTPS
+func (l Listen) IsTLS() bool {
+	return l.CertFile != `` && l.KeyFile != ``
 }
 
 func (api *Api) MaskedToken(params string) string {
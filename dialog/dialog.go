@@ -0,0 +1,214 @@
+package dialog
+
+import (
+	"context"
+	"fmt"
+	"github.com/sepuka/vkbotserver/api/button"
+	"github.com/sepuka/vkbotserver/domain"
+	"github.com/sepuka/vkbotserver/errors"
+	"strings"
+	"time"
+)
+
+type (
+	// Key identifies the dialog of the peer with the group
+	Key struct {
+		GroupId int32
+		PeerId  int32
+	}
+
+	// Session is the current state of the dialog and the data collected by the dialog
+	Session struct {
+		State     string            `json:"state"`
+		Data      map[string]string `json:"data"`
+		UpdatedAt time.Time         `json:"updated_at"`
+	}
+
+	// Store keeps sessions, Get returns nil without error if there is no session
+	Store interface {
+		Get(ctx context.Context, key Key) (*Session, error)
+		Set(ctx context.Context, key Key, session *Session, ttl time.Duration) error
+		Delete(ctx context.Context, key Key) error
+	}
+
+	// Hook is called when the dialog enters or leaves the state
+	Hook func(ctx context.Context, req *domain.Request, session *Session) error
+
+	// State of the dialog, all hooks are optional
+	// Handle is called for messages which trigger no transition, it returns the next state or an empty string to stay
+	State struct {
+		Name    string
+		OnEnter Hook
+		Handle  func(ctx context.Context, req *domain.Request, payload *button.Payload, session *Session) (string, error)
+		OnLeave Hook
+	}
+
+	// Transition moves the dialog to the state To when the message text equals Text ignoring case
+	// or the button payload command equals Command. Empty From means any state
+	Transition struct {
+		From    string
+		Text    string
+		Command string
+		To      string
+	}
+
+	// Machine leads multi-step dialogs, it's a message.Handler so it may be used as a router route or a fallback
+	Machine struct {
+		initial     string
+		states      map[string]State
+		transitions []Transition
+		store       Store
+		timeout     time.Duration
+		now         func() time.Time
+	}
+)
+
+// NewMachine creates dialogs which start in the initial state and reset to it after the timeout of inactivity
+// zero timeout means dialogs never expire
+func NewMachine(store Store, initial string, timeout time.Duration) *Machine {
+	return &Machine{
+		initial: initial,
+		states:  map[string]State{initial: {Name: initial}},
+		store:   store,
+		timeout: timeout,
+		now:     time.Now,
+	}
+}
+
+// State adds the state or replaces the existing one with the same name
+func (m *Machine) State(state State) *Machine {
+	m.states[state.Name] = state
+
+	return m
+}
+
+// Transition adds the transition, transitions are checked in order of adding
+func (m *Machine) Transition(transition Transition) *Machine {
+	m.transitions = append(m.transitions, transition)
+
+	return m
+}
+
+// Reset forgets the dialog of the peer
+func (m *Machine) Reset(ctx context.Context, key Key) error {
+	return m.store.Delete(ctx, key)
+}
+
+func (m *Machine) Handle(req *domain.Request, payload *button.Payload) error {
+	return m.HandleContext(context.Background(), req, payload)
+}
+
+func (m *Machine) HandleContext(ctx context.Context, req *domain.Request, payload *button.Payload) error {
+	var (
+		key     = KeyOf(req)
+		now     = m.now()
+		session *Session
+		next    string
+		err     error
+	)
+
+	if session, err = m.store.Get(ctx, key); err != nil {
+		return err
+	}
+
+	if session == nil || m.isExpired(session, now) {
+		session = m.newSession()
+	}
+
+	if next, err = m.next(ctx, req, payload, session); err != nil {
+		return err
+	}
+
+	if next != `` {
+		if err = m.move(ctx, req, session, next); err != nil {
+			return err
+		}
+	}
+
+	if session.State == m.initial && len(session.Data) == 0 {
+		return m.store.Delete(ctx, key)
+	}
+
+	session.UpdatedAt = now
+
+	return m.store.Set(ctx, key, session, m.timeout)
+}
+
+// KeyOf returns the dialog key of the message or the callback button event
+func KeyOf(req *domain.Request) Key {
+	var key = Key{
+		GroupId: req.GroupId,
+		PeerId:  req.Object.Message.PeerId,
+	}
+
+	if event, err := req.MessageEvent(); err == nil {
+		key.PeerId = int32(event.PeerId)
+	}
+
+	return key
+}
+
+func (m *Machine) next(ctx context.Context, req *domain.Request, payload *button.Payload, session *Session) (string, error) {
+	var text = strings.TrimSpace(req.Object.Message.Text)
+
+	for _, transition := range m.transitions {
+		if transition.From != `` && transition.From != session.State {
+			continue
+		}
+
+		if transition.Text != `` && strings.EqualFold(transition.Text, text) {
+			return transition.To, nil
+		}
+
+		if transition.Command != `` && payload != nil && payload.Command == transition.Command {
+			return transition.To, nil
+		}
+	}
+
+	if state := m.states[session.State]; state.Handle != nil {
+		return state.Handle(ctx, req, payload, session)
+	}
+
+	return ``, nil
+}
+
+func (m *Machine) move(ctx context.Context, req *domain.Request, session *Session, next string) error {
+	var (
+		current   = m.states[session.State]
+		nextState State
+		ok        bool
+		err       error
+	)
+
+	if nextState, ok = m.states[next]; !ok {
+		return errors.NewUnknownStateError(fmt.Sprintf(`dialog state "%s" is not defined`, next))
+	}
+
+	if current.OnLeave != nil {
+		if err = current.OnLeave(ctx, req, session); err != nil {
+			return err
+		}
+	}
+
+	session.State = next
+	if next == m.initial {
+		session.Data = map[string]string{}
+	}
+
+	if nextState.OnEnter != nil {
+		return nextState.OnEnter(ctx, req, session)
+	}
+
+	return nil
+}
+
+func (m *Machine) newSession() *Session {
+	return &Session{
+		State: m.initial,
+		Data:  map[string]string{},
+	}
+}
+
+func (m *Machine) isExpired(session *Session, now time.Time) bool {
+	return m.timeout > 0 && now.Sub(session.UpdatedAt) >= m.timeout
+}
@@ -0,0 +1,169 @@
+package dialog
+
+import (
+	"context"
+	"fmt"
+	"github.com/mailru/easyjson"
+	"github.com/sepuka/vkbotserver/api/button"
+	"github.com/sepuka/vkbotserver/domain"
+	"github.com/sepuka/vkbotserver/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newBookingMachine(store Store, trace *[]string) *Machine {
+	var enter = func(name string) Hook {
+		return func(ctx context.Context, req *domain.Request, session *Session) error {
+			*trace = append(*trace, `enter `+name)
+			return nil
+		}
+	}
+
+	return NewMachine(store, `idle`, time.Minute).
+		State(State{
+			Name:    `date`,
+			OnEnter: enter(`date`),
+			Handle: func(ctx context.Context, req *domain.Request, payload *button.Payload, session *Session) (string, error) {
+				session.Data[`date`] = req.Object.Message.Text
+				return `confirm`, nil
+			},
+			OnLeave: func(ctx context.Context, req *domain.Request, session *Session) error {
+				*trace = append(*trace, `leave date`)
+				return nil
+			},
+		}).
+		State(State{
+			Name:    `confirm`,
+			OnEnter: enter(`confirm`),
+		}).
+		State(State{
+			Name:    `idle`,
+			OnEnter: enter(`idle`),
+		}).
+		Transition(Transition{From: `idle`, Text: `book`, To: `date`}).
+		Transition(Transition{From: `confirm`, Command: `yes`, To: `idle`}).
+		Transition(Transition{Text: `cancel`, To: `idle`})
+}
+
+func newRequest(text string) *domain.Request {
+	return &domain.Request{
+		Type:    domain.EventMessageNew,
+		GroupId: 1,
+		Object: domain.Object{
+			Message: domain.Message{PeerId: 557404793, Text: text},
+		},
+	}
+}
+
+func TestMachine_Handle(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		key     = Key{GroupId: 1, PeerId: 557404793}
+		trace   []string
+		store   = NewMemoryStore()
+		machine = newBookingMachine(store, &trace)
+		session *Session
+		err     error
+	)
+
+	assert.Nil(t, machine.Handle(newRequest(`hello`), nil))
+	session, err = store.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Nil(t, session, `idle dialog is not stored`)
+
+	assert.Nil(t, machine.Handle(newRequest(`Book`), nil))
+	session, _ = store.Get(ctx, key)
+	assert.Equal(t, `date`, session.State)
+
+	assert.Nil(t, machine.Handle(newRequest(`tomorrow`), nil))
+	session, _ = store.Get(ctx, key)
+	assert.Equal(t, `confirm`, session.State)
+	assert.Equal(t, `tomorrow`, session.Data[`date`])
+
+	assert.Nil(t, machine.Handle(newRequest(`Yes`), &button.Payload{Command: `yes`}))
+	session, _ = store.Get(ctx, key)
+	assert.Nil(t, session)
+
+	assert.Equal(t, []string{`enter date`, `leave date`, `enter confirm`, `enter idle`}, trace)
+}
+
+func newCallbackRequest(peerId int) *domain.Request {
+	var req = &domain.Request{}
+
+	_ = easyjson.Unmarshal([]byte(fmt.Sprintf(`{"type": "message_event", "group_id": 1, "object": {"user_id": %d, "peer_id": %d, "event_id": "abc"}}`, peerId, peerId)), req)
+
+	return req
+}
+
+func TestMachine_HandleCallbackButtons(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		store   = NewMemoryStore()
+		machine = NewMachine(store, `idle`, time.Minute).
+			State(State{Name: `date`}).
+			Transition(Transition{From: `idle`, Command: `book`, To: `date`}).
+			Transition(Transition{Command: `cancel`, To: `idle`})
+	)
+
+	assert.Nil(t, machine.Handle(newCallbackRequest(557404793), &button.Payload{Command: `book`}))
+	assert.Nil(t, machine.Handle(newCallbackRequest(100500), &button.Payload{Command: `cancel`}))
+
+	session, err := store.Get(ctx, Key{GroupId: 1, PeerId: 557404793})
+	assert.Nil(t, err)
+	if assert.NotNil(t, session, `each peer has its own dialog`) {
+		assert.Equal(t, `date`, session.State)
+	}
+
+	session, _ = store.Get(ctx, Key{GroupId: 1, PeerId: 100500})
+	assert.Nil(t, session)
+}
+
+func TestMachine_HandleTimeout(t *testing.T) {
+	var (
+		now     = time.Date(2022, 5, 20, 12, 0, 0, 0, time.UTC)
+		key     = Key{GroupId: 1, PeerId: 557404793}
+		trace   []string
+		store   = NewMemoryStore()
+		machine = newBookingMachine(store, &trace)
+	)
+
+	machine.now = func() time.Time { return now }
+
+	assert.Nil(t, machine.Handle(newRequest(`book`), nil))
+
+	now = now.Add(2 * time.Minute)
+	assert.Nil(t, machine.Handle(newRequest(`tomorrow`), nil))
+
+	session, err := store.Get(context.Background(), key)
+	assert.Nil(t, err)
+	assert.Nil(t, session, `expired dialog starts from the initial state`)
+	assert.Equal(t, []string{`enter date`}, trace)
+}
+
+func TestMachine_HandleUnknownState(t *testing.T) {
+	var machine = NewMachine(NewMemoryStore(), `idle`, 0).
+		Transition(Transition{Text: `go`, To: `nowhere`})
+
+	assert.ErrorIs(t, machine.Handle(newRequest(`go`), nil), errors.UnknownState)
+}
+
+func TestMemoryStore_Expiration(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		now   = time.Date(2022, 5, 20, 12, 0, 0, 0, time.UTC)
+		key   = Key{GroupId: 1, PeerId: 2}
+		store = NewMemoryStore()
+	)
+
+	store.now = func() time.Time { return now }
+
+	assert.Nil(t, store.Set(ctx, key, &Session{State: `date`, Data: map[string]string{}}, time.Minute))
+
+	session, _ := store.Get(ctx, key)
+	assert.Equal(t, `date`, session.State)
+
+	now = now.Add(time.Minute)
+	session, _ = store.Get(ctx, key)
+	assert.Nil(t, session)
+}
@@ -0,0 +1,138 @@
+package dialog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"sync"
+	"time"
+)
+
+const (
+	sessionKeyTmpl = `vkbot_server_dialog_%d_%d`
+)
+
+type (
+	memoryStore struct {
+		mu       sync.Mutex
+		sessions map[Key]memorySession
+		now      func() time.Time
+	}
+
+	memorySession struct {
+		session Session
+		expires time.Time
+	}
+
+	redisStore struct {
+		client *redis.Client
+	}
+)
+
+// NewMemoryStore creates store which keeps sessions in the process memory
+func NewMemoryStore() *memoryStore {
+	return &memoryStore{
+		sessions: map[Key]memorySession{},
+		now:      time.Now,
+	}
+}
+
+func (s *memoryStore) Get(ctx context.Context, key Key) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var stored, ok = s.sessions[key]
+	if !ok {
+		return nil, nil
+	}
+
+	if !stored.expires.IsZero() && !s.now().Before(stored.expires) {
+		delete(s.sessions, key)
+		return nil, nil
+	}
+
+	return stored.session.clone(), nil
+}
+
+func (s *memoryStore) Set(ctx context.Context, key Key, session *Session, ttl time.Duration) error {
+	var stored = memorySession{session: *session.clone()}
+
+	if ttl > 0 {
+		stored.expires = s.now().Add(ttl)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions[key] = stored
+
+	return nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, key Key) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, key)
+
+	return nil
+}
+
+// NewRedisStore creates store which keeps sessions in redis as JSON
+func NewRedisStore(client *redis.Client) *redisStore {
+	return &redisStore{
+		client: client,
+	}
+}
+
+func (s *redisStore) Get(ctx context.Context, key Key) (*Session, error) {
+	var (
+		session = &Session{}
+		data    []byte
+		err     error
+	)
+
+	if data, err = s.client.Get(ctx, s.key(key)).Bytes(); err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (s *redisStore) Set(ctx context.Context, key Key, session *Session, ttl time.Duration) error {
+	var data, err = json.Marshal(session)
+
+	if err != nil {
+		return err
+	}
+
+	return s.client.Set(ctx, s.key(key), data, ttl).Err()
+}
+
+func (s *redisStore) Delete(ctx context.Context, key Key) error {
+	return s.client.Del(ctx, s.key(key)).Err()
+}
+
+func (s *redisStore) key(key Key) string {
+	return fmt.Sprintf(sessionKeyTmpl, key.GroupId, key.PeerId)
+}
+
+func (s *Session) clone() *Session {
+	var clone = *s
+
+	clone.Data = make(map[string]string, len(s.Data))
+	for k, v := range s.Data {
+		clone.Data[k] = v
+	}
+
+	return &clone
+}
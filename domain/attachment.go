@@ -0,0 +1,214 @@
+package domain
+
+// Attachment types, see https://dev.vk.com/reference/objects/attachments-message
+const (
+	AttachmentPhoto        = `photo`
+	AttachmentVideo        = `video`
+	AttachmentAudio        = `audio`
+	AttachmentDoc          = `doc`
+	AttachmentLink         = `link`
+	AttachmentSticker      = `sticker`
+	AttachmentWall         = `wall`
+	AttachmentAudioMessage = `audio_message`
+)
+
+// photo size types from the smallest to the largest, they're used when sizes have no dimensions
+var photoSizeRank = map[string]int{
+	`s`: 1, `m`: 2, `x`: 3, `o`: 4, `p`: 5, `q`: 6, `r`: 7, `y`: 8, `z`: 9, `w`: 10,
+}
+
+type (
+	// Attachment of a message or a wall post, the field named by Type is filled only
+	//easyjson:json
+	Attachment struct {
+		Type         string        `json:"type"`
+		Photo        *Photo        `json:"photo"`
+		Video        *Video        `json:"video"`
+		Audio        *Audio        `json:"audio"`
+		Doc          *Doc          `json:"doc"`
+		Link         *Link         `json:"link"`
+		Sticker      *Sticker      `json:"sticker"`
+		Wall         *WallPost     `json:"wall"`
+		AudioMessage *AudioMessage `json:"audio_message"`
+	}
+
+	// PhotoSize is one of the photo copies, type is a letter like s, m, x, y, z, w
+	PhotoSize struct {
+		Type   string `json:"type"`
+		Url    string `json:"url"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
+	}
+
+	Photo struct {
+		Id        int         `json:"id"`
+		AlbumId   int         `json:"album_id"`
+		OwnerId   int         `json:"owner_id"`
+		UserId    int         `json:"user_id"`
+		Text      string      `json:"text"`
+		Date      int         `json:"date"`
+		Sizes     []PhotoSize `json:"sizes"`
+		AccessKey string      `json:"access_key"`
+	}
+
+	Video struct {
+		Id          int    `json:"id"`
+		OwnerId     int    `json:"owner_id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Duration    int    `json:"duration"`
+		Date        int    `json:"date"`
+		AccessKey   string `json:"access_key"`
+	}
+
+	Audio struct {
+		Id       int    `json:"id"`
+		OwnerId  int    `json:"owner_id"`
+		Artist   string `json:"artist"`
+		Title    string `json:"title"`
+		Duration int    `json:"duration"`
+		Url      string `json:"url"`
+	}
+
+	// Doc type is 1 for text, 2 for archives, 3 for gif, 4 for images, 5 for audio, 6 for video, 7 for e-books, 8 for unknown
+	Doc struct {
+		Id        int    `json:"id"`
+		OwnerId   int    `json:"owner_id"`
+		Title     string `json:"title"`
+		Size      int    `json:"size"`
+		Ext       string `json:"ext"`
+		Url       string `json:"url"`
+		Date      int    `json:"date"`
+		Type      int    `json:"type"`
+		AccessKey string `json:"access_key"`
+	}
+
+	Link struct {
+		Url         string `json:"url"`
+		Title       string `json:"title"`
+		Caption     string `json:"caption"`
+		Description string `json:"description"`
+		Photo       *Photo `json:"photo"`
+	}
+
+	StickerImage struct {
+		Url    string `json:"url"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
+	}
+
+	Sticker struct {
+		ProductId            int            `json:"product_id"`
+		StickerId            int            `json:"sticker_id"`
+		Images               []StickerImage `json:"images"`
+		ImagesWithBackground []StickerImage `json:"images_with_background"`
+	}
+
+	AudioMessage struct {
+		Id        int    `json:"id"`
+		OwnerId   int    `json:"owner_id"`
+		Duration  int    `json:"duration"`
+		Waveform  []int  `json:"waveform"`
+		LinkOgg   string `json:"link_ogg"`
+		LinkMp3   string `json:"link_mp3"`
+		AccessKey string `json:"access_key"`
+	}
+
+	Coordinates struct {
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+	}
+
+	Place struct {
+		Id        int     `json:"id"`
+		Title     string  `json:"title"`
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+		Country   string  `json:"country"`
+		City      string  `json:"city"`
+	}
+
+	// Geo is the location attached to a message
+	Geo struct {
+		Type        string      `json:"type"`
+		Coordinates Coordinates `json:"coordinates"`
+		Place       *Place      `json:"place"`
+	}
+)
+
+// Largest returns the largest copy of the photo
+func (p Photo) Largest() (PhotoSize, bool) {
+	var (
+		largest PhotoSize
+		found   bool
+	)
+
+	for _, size := range p.Sizes {
+		if !found || size.isLarger(largest) {
+			largest = size
+			found = true
+		}
+	}
+
+	return largest, found
+}
+
+func (s PhotoSize) isLarger(other PhotoSize) bool {
+	var area, otherArea = s.Width * s.Height, other.Width * other.Height
+
+	if area != otherArea {
+		return area > otherArea
+	}
+
+	return photoSizeRank[s.Type] > photoSizeRank[other.Type]
+}
+
+// Photos returns photos attached to the message
+func (m Message) Photos() []Photo {
+	var photos []Photo
+
+	for _, attachment := range m.Attachments {
+		if attachment.Type == AttachmentPhoto && attachment.Photo != nil {
+			photos = append(photos, *attachment.Photo)
+		}
+	}
+
+	return photos
+}
+
+// Docs returns documents attached to the message
+func (m Message) Docs() []Doc {
+	var docs []Doc
+
+	for _, attachment := range m.Attachments {
+		if attachment.Type == AttachmentDoc && attachment.Doc != nil {
+			docs = append(docs, *attachment.Doc)
+		}
+	}
+
+	return docs
+}
+
+// AudioMessages returns voice messages attached to the message
+func (m Message) AudioMessages() []AudioMessage {
+	var audios []AudioMessage
+
+	for _, attachment := range m.Attachments {
+		if attachment.Type == AttachmentAudioMessage && attachment.AudioMessage != nil {
+			audios = append(audios, *attachment.AudioMessage)
+		}
+	}
+
+	return audios
+}
+
+// LargestPhotoURL returns url of the largest copy of the first attached photo or an empty string
+func (m Message) LargestPhotoURL() string {
+	for _, photo := range m.Photos() {
+		if size, ok := photo.Largest(); ok {
+			return size.Url
+		}
+	}
+
+	return ``
+}
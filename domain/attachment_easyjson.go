@@ -0,0 +1,1063 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package domain
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain(in *jlexer.Lexer, out *Attachment) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "type":
+			out.Type = string(in.String())
+		case "photo":
+			if in.IsNull() {
+				in.Skip()
+				out.Photo = nil
+			} else {
+				if out.Photo == nil {
+					out.Photo = new(Photo)
+				}
+				easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain1(in, out.Photo)
+			}
+		case "video":
+			if in.IsNull() {
+				in.Skip()
+				out.Video = nil
+			} else {
+				if out.Video == nil {
+					out.Video = new(Video)
+				}
+				easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain2(in, out.Video)
+			}
+		case "audio":
+			if in.IsNull() {
+				in.Skip()
+				out.Audio = nil
+			} else {
+				if out.Audio == nil {
+					out.Audio = new(Audio)
+				}
+				easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain3(in, out.Audio)
+			}
+		case "doc":
+			if in.IsNull() {
+				in.Skip()
+				out.Doc = nil
+			} else {
+				if out.Doc == nil {
+					out.Doc = new(Doc)
+				}
+				easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain4(in, out.Doc)
+			}
+		case "link":
+			if in.IsNull() {
+				in.Skip()
+				out.Link = nil
+			} else {
+				if out.Link == nil {
+					out.Link = new(Link)
+				}
+				easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain5(in, out.Link)
+			}
+		case "sticker":
+			if in.IsNull() {
+				in.Skip()
+				out.Sticker = nil
+			} else {
+				if out.Sticker == nil {
+					out.Sticker = new(Sticker)
+				}
+				easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain6(in, out.Sticker)
+			}
+		case "wall":
+			if in.IsNull() {
+				in.Skip()
+				out.Wall = nil
+			} else {
+				if out.Wall == nil {
+					out.Wall = new(WallPost)
+				}
+				(*out.Wall).UnmarshalEasyJSON(in)
+			}
+		case "audio_message":
+			if in.IsNull() {
+				in.Skip()
+				out.AudioMessage = nil
+			} else {
+				if out.AudioMessage == nil {
+					out.AudioMessage = new(AudioMessage)
+				}
+				easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain7(in, out.AudioMessage)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain(out *jwriter.Writer, in Attachment) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"photo\":"
+		out.RawString(prefix)
+		if in.Photo == nil {
+			out.RawString("null")
+		} else {
+			easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain1(out, *in.Photo)
+		}
+	}
+	{
+		const prefix string = ",\"video\":"
+		out.RawString(prefix)
+		if in.Video == nil {
+			out.RawString("null")
+		} else {
+			easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain2(out, *in.Video)
+		}
+	}
+	{
+		const prefix string = ",\"audio\":"
+		out.RawString(prefix)
+		if in.Audio == nil {
+			out.RawString("null")
+		} else {
+			easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain3(out, *in.Audio)
+		}
+	}
+	{
+		const prefix string = ",\"doc\":"
+		out.RawString(prefix)
+		if in.Doc == nil {
+			out.RawString("null")
+		} else {
+			easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain4(out, *in.Doc)
+		}
+	}
+	{
+		const prefix string = ",\"link\":"
+		out.RawString(prefix)
+		if in.Link == nil {
+			out.RawString("null")
+		} else {
+			easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain5(out, *in.Link)
+		}
+	}
+	{
+		const prefix string = ",\"sticker\":"
+		out.RawString(prefix)
+		if in.Sticker == nil {
+			out.RawString("null")
+		} else {
+			easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain6(out, *in.Sticker)
+		}
+	}
+	{
+		const prefix string = ",\"wall\":"
+		out.RawString(prefix)
+		if in.Wall == nil {
+			out.RawString("null")
+		} else {
+			(*in.Wall).MarshalEasyJSON(out)
+		}
+	}
+	{
+		const prefix string = ",\"audio_message\":"
+		out.RawString(prefix)
+		if in.AudioMessage == nil {
+			out.RawString("null")
+		} else {
+			easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain7(out, *in.AudioMessage)
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Attachment) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Attachment) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Attachment) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Attachment) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain(l, v)
+}
+func easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain7(in *jlexer.Lexer, out *AudioMessage) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.Id = int(in.Int())
+		case "owner_id":
+			out.OwnerId = int(in.Int())
+		case "duration":
+			out.Duration = int(in.Int())
+		case "waveform":
+			if in.IsNull() {
+				in.Skip()
+				out.Waveform = nil
+			} else {
+				in.Delim('[')
+				if out.Waveform == nil {
+					if !in.IsDelim(']') {
+						out.Waveform = make([]int, 0, 8)
+					} else {
+						out.Waveform = []int{}
+					}
+				} else {
+					out.Waveform = (out.Waveform)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 int
+					v1 = int(in.Int())
+					out.Waveform = append(out.Waveform, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "link_ogg":
+			out.LinkOgg = string(in.String())
+		case "link_mp3":
+			out.LinkMp3 = string(in.String())
+		case "access_key":
+			out.AccessKey = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain7(out *jwriter.Writer, in AudioMessage) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Id))
+	}
+	{
+		const prefix string = ",\"owner_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.OwnerId))
+	}
+	{
+		const prefix string = ",\"duration\":"
+		out.RawString(prefix)
+		out.Int(int(in.Duration))
+	}
+	{
+		const prefix string = ",\"waveform\":"
+		out.RawString(prefix)
+		if in.Waveform == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Waveform {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				out.Int(int(v3))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"link_ogg\":"
+		out.RawString(prefix)
+		out.String(string(in.LinkOgg))
+	}
+	{
+		const prefix string = ",\"link_mp3\":"
+		out.RawString(prefix)
+		out.String(string(in.LinkMp3))
+	}
+	{
+		const prefix string = ",\"access_key\":"
+		out.RawString(prefix)
+		out.String(string(in.AccessKey))
+	}
+	out.RawByte('}')
+}
+func easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain6(in *jlexer.Lexer, out *Sticker) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "product_id":
+			out.ProductId = int(in.Int())
+		case "sticker_id":
+			out.StickerId = int(in.Int())
+		case "images":
+			if in.IsNull() {
+				in.Skip()
+				out.Images = nil
+			} else {
+				in.Delim('[')
+				if out.Images == nil {
+					if !in.IsDelim(']') {
+						out.Images = make([]StickerImage, 0, 2)
+					} else {
+						out.Images = []StickerImage{}
+					}
+				} else {
+					out.Images = (out.Images)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 StickerImage
+					easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain8(in, &v4)
+					out.Images = append(out.Images, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "images_with_background":
+			if in.IsNull() {
+				in.Skip()
+				out.ImagesWithBackground = nil
+			} else {
+				in.Delim('[')
+				if out.ImagesWithBackground == nil {
+					if !in.IsDelim(']') {
+						out.ImagesWithBackground = make([]StickerImage, 0, 2)
+					} else {
+						out.ImagesWithBackground = []StickerImage{}
+					}
+				} else {
+					out.ImagesWithBackground = (out.ImagesWithBackground)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v5 StickerImage
+					easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain8(in, &v5)
+					out.ImagesWithBackground = append(out.ImagesWithBackground, v5)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain6(out *jwriter.Writer, in Sticker) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"product_id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.ProductId))
+	}
+	{
+		const prefix string = ",\"sticker_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.StickerId))
+	}
+	{
+		const prefix string = ",\"images\":"
+		out.RawString(prefix)
+		if in.Images == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v6, v7 := range in.Images {
+				if v6 > 0 {
+					out.RawByte(',')
+				}
+				easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain8(out, v7)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"images_with_background\":"
+		out.RawString(prefix)
+		if in.ImagesWithBackground == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v8, v9 := range in.ImagesWithBackground {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain8(out, v9)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain8(in *jlexer.Lexer, out *StickerImage) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "url":
+			out.Url = string(in.String())
+		case "width":
+			out.Width = int(in.Int())
+		case "height":
+			out.Height = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain8(out *jwriter.Writer, in StickerImage) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"url\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Url))
+	}
+	{
+		const prefix string = ",\"width\":"
+		out.RawString(prefix)
+		out.Int(int(in.Width))
+	}
+	{
+		const prefix string = ",\"height\":"
+		out.RawString(prefix)
+		out.Int(int(in.Height))
+	}
+	out.RawByte('}')
+}
+func easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain5(in *jlexer.Lexer, out *Link) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "url":
+			out.Url = string(in.String())
+		case "title":
+			out.Title = string(in.String())
+		case "caption":
+			out.Caption = string(in.String())
+		case "description":
+			out.Description = string(in.String())
+		case "photo":
+			if in.IsNull() {
+				in.Skip()
+				out.Photo = nil
+			} else {
+				if out.Photo == nil {
+					out.Photo = new(Photo)
+				}
+				easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain1(in, out.Photo)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain5(out *jwriter.Writer, in Link) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"url\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Url))
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"caption\":"
+		out.RawString(prefix)
+		out.String(string(in.Caption))
+	}
+	{
+		const prefix string = ",\"description\":"
+		out.RawString(prefix)
+		out.String(string(in.Description))
+	}
+	{
+		const prefix string = ",\"photo\":"
+		out.RawString(prefix)
+		if in.Photo == nil {
+			out.RawString("null")
+		} else {
+			easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain1(out, *in.Photo)
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain4(in *jlexer.Lexer, out *Doc) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.Id = int(in.Int())
+		case "owner_id":
+			out.OwnerId = int(in.Int())
+		case "title":
+			out.Title = string(in.String())
+		case "size":
+			out.Size = int(in.Int())
+		case "ext":
+			out.Ext = string(in.String())
+		case "url":
+			out.Url = string(in.String())
+		case "date":
+			out.Date = int(in.Int())
+		case "type":
+			out.Type = int(in.Int())
+		case "access_key":
+			out.AccessKey = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain4(out *jwriter.Writer, in Doc) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Id))
+	}
+	{
+		const prefix string = ",\"owner_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.OwnerId))
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"size\":"
+		out.RawString(prefix)
+		out.Int(int(in.Size))
+	}
+	{
+		const prefix string = ",\"ext\":"
+		out.RawString(prefix)
+		out.String(string(in.Ext))
+	}
+	{
+		const prefix string = ",\"url\":"
+		out.RawString(prefix)
+		out.String(string(in.Url))
+	}
+	{
+		const prefix string = ",\"date\":"
+		out.RawString(prefix)
+		out.Int(int(in.Date))
+	}
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.Int(int(in.Type))
+	}
+	{
+		const prefix string = ",\"access_key\":"
+		out.RawString(prefix)
+		out.String(string(in.AccessKey))
+	}
+	out.RawByte('}')
+}
+func easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain3(in *jlexer.Lexer, out *Audio) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.Id = int(in.Int())
+		case "owner_id":
+			out.OwnerId = int(in.Int())
+		case "artist":
+			out.Artist = string(in.String())
+		case "title":
+			out.Title = string(in.String())
+		case "duration":
+			out.Duration = int(in.Int())
+		case "url":
+			out.Url = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain3(out *jwriter.Writer, in Audio) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Id))
+	}
+	{
+		const prefix string = ",\"owner_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.OwnerId))
+	}
+	{
+		const prefix string = ",\"artist\":"
+		out.RawString(prefix)
+		out.String(string(in.Artist))
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"duration\":"
+		out.RawString(prefix)
+		out.Int(int(in.Duration))
+	}
+	{
+		const prefix string = ",\"url\":"
+		out.RawString(prefix)
+		out.String(string(in.Url))
+	}
+	out.RawByte('}')
+}
+func easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain2(in *jlexer.Lexer, out *Video) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.Id = int(in.Int())
+		case "owner_id":
+			out.OwnerId = int(in.Int())
+		case "title":
+			out.Title = string(in.String())
+		case "description":
+			out.Description = string(in.String())
+		case "duration":
+			out.Duration = int(in.Int())
+		case "date":
+			out.Date = int(in.Int())
+		case "access_key":
+			out.AccessKey = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain2(out *jwriter.Writer, in Video) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Id))
+	}
+	{
+		const prefix string = ",\"owner_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.OwnerId))
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"description\":"
+		out.RawString(prefix)
+		out.String(string(in.Description))
+	}
+	{
+		const prefix string = ",\"duration\":"
+		out.RawString(prefix)
+		out.Int(int(in.Duration))
+	}
+	{
+		const prefix string = ",\"date\":"
+		out.RawString(prefix)
+		out.Int(int(in.Date))
+	}
+	{
+		const prefix string = ",\"access_key\":"
+		out.RawString(prefix)
+		out.String(string(in.AccessKey))
+	}
+	out.RawByte('}')
+}
+func easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain1(in *jlexer.Lexer, out *Photo) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.Id = int(in.Int())
+		case "album_id":
+			out.AlbumId = int(in.Int())
+		case "owner_id":
+			out.OwnerId = int(in.Int())
+		case "user_id":
+			out.UserId = int(in.Int())
+		case "text":
+			out.Text = string(in.String())
+		case "date":
+			out.Date = int(in.Int())
+		case "sizes":
+			if in.IsNull() {
+				in.Skip()
+				out.Sizes = nil
+			} else {
+				in.Delim('[')
+				if out.Sizes == nil {
+					if !in.IsDelim(']') {
+						out.Sizes = make([]PhotoSize, 0, 1)
+					} else {
+						out.Sizes = []PhotoSize{}
+					}
+				} else {
+					out.Sizes = (out.Sizes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v10 PhotoSize
+					easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain9(in, &v10)
+					out.Sizes = append(out.Sizes, v10)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "access_key":
+			out.AccessKey = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain1(out *jwriter.Writer, in Photo) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Id))
+	}
+	{
+		const prefix string = ",\"album_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.AlbumId))
+	}
+	{
+		const prefix string = ",\"owner_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.OwnerId))
+	}
+	{
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.UserId))
+	}
+	{
+		const prefix string = ",\"text\":"
+		out.RawString(prefix)
+		out.String(string(in.Text))
+	}
+	{
+		const prefix string = ",\"date\":"
+		out.RawString(prefix)
+		out.Int(int(in.Date))
+	}
+	{
+		const prefix string = ",\"sizes\":"
+		out.RawString(prefix)
+		if in.Sizes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v11, v12 := range in.Sizes {
+				if v11 > 0 {
+					out.RawByte(',')
+				}
+				easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain9(out, v12)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"access_key\":"
+		out.RawString(prefix)
+		out.String(string(in.AccessKey))
+	}
+	out.RawByte('}')
+}
+func easyjson76362c5bDecodeGithubComSepukaVkbotserverDomain9(in *jlexer.Lexer, out *PhotoSize) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "type":
+			out.Type = string(in.String())
+		case "url":
+			out.Url = string(in.String())
+		case "width":
+			out.Width = int(in.Int())
+		case "height":
+			out.Height = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson76362c5bEncodeGithubComSepukaVkbotserverDomain9(out *jwriter.Writer, in PhotoSize) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"url\":"
+		out.RawString(prefix)
+		out.String(string(in.Url))
+	}
+	{
+		const prefix string = ",\"width\":"
+		out.RawString(prefix)
+		out.Int(int(in.Width))
+	}
+	{
+		const prefix string = ",\"height\":"
+		out.RawString(prefix)
+		out.Int(int(in.Height))
+	}
+	out.RawByte('}')
+}
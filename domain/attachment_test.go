@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"github.com/mailru/easyjson"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMessage_Attachments(t *testing.T) {
+	const payload = `{
+  "group_id": 199999999,
+  "type": "message_new",
+  "event_id": "c1",
+  "v": "5.131",
+  "object": {
+    "message": {
+      "date": 1653041018,
+      "from_id": 557404793,
+      "id": 0,
+      "out": 0,
+      "conversation_message_id": 570,
+      "peer_id": 557404793,
+      "random_id": 0,
+      "text": "look",
+      "attachments": [
+        {"type": "photo", "photo": {"album_id": -3, "date": 1653041000, "id": 457239017, "owner_id": 557404793, "access_key": "ak1", "sizes": [
+          {"height": 75, "url": "https://sun9-1.userapi.com/s.jpg", "type": "s", "width": 56},
+          {"height": 1280, "url": "https://sun9-1.userapi.com/w.jpg", "type": "w", "width": 960},
+          {"height": 604, "url": "https://sun9-1.userapi.com/x.jpg", "type": "x", "width": 453}
+        ], "text": ""}},
+        {"type": "doc", "doc": {"id": 624170511, "owner_id": 557404793, "title": "report.pdf", "size": 35437, "ext": "pdf", "date": 1653041000, "type": 1, "url": "https://vk.com/doc557404793_624170511", "access_key": "ak2"}},
+        {"type": "audio_message", "audio_message": {"duration": 2, "id": 624170512, "link_mp3": "https://psv4.userapi.com/voice.mp3", "link_ogg": "https://psv4.userapi.com/voice.ogg", "owner_id": 557404793, "access_key": "ak3", "waveform": [0, 5, 12, 31]}},
+        {"type": "sticker", "sticker": {"product_id": 279, "sticker_id": 9014, "images": [{"url": "https://vk.com/sticker/64.png", "width": 64, "height": 64}], "images_with_background": []}},
+        {"type": "link", "link": {"url": "https://github.com/Sepuka/vkbotserver", "title": "vkbotserver", "caption": "github.com", "description": ""}},
+        {"type": "wall", "wall": {"id": 42, "from_id": -199999999, "owner_id": -199999999, "date": 1653041018, "post_type": "post", "text": "News", "attachments": []}}
+      ],
+      "fwd_messages": [
+        {"date": 1653040000, "from_id": 1, "text": "forwarded", "attachments": [], "conversation_message_id": 100, "peer_id": 557404793, "id": 0,
+         "fwd_messages": [{"date": 1653030000, "from_id": 2, "text": "nested", "attachments": [], "conversation_message_id": 50, "peer_id": 557404793, "id": 0}]}
+      ],
+      "reply_message": {"date": 1653041000, "from_id": -199999999, "text": "question", "attachments": [], "conversation_message_id": 569, "peer_id": 557404793, "id": 1234},
+      "geo": {"type": "point", "coordinates": {"latitude": 55.7558, "longitude": 37.6173}, "place": {"country": "Russia", "city": "Moscow", "title": "Moscow, Russia"}},
+      "important": false,
+      "is_hidden": false
+    },
+    "client_info": {"button_actions": ["text"], "keyboard": true, "inline_keyboard": true, "carousel": true, "lang_id": 0}
+  }
+}`
+
+	var (
+		req = Request{}
+		msg *Message
+		err error
+	)
+
+	assert.Nil(t, easyjson.Unmarshal([]byte(payload), &req))
+
+	msg, err = req.MessageNew()
+	assert.Nil(t, err)
+
+	assert.Len(t, msg.Attachments, 6)
+	assert.Len(t, msg.Photos(), 1)
+	assert.Equal(t, `https://sun9-1.userapi.com/w.jpg`, msg.LargestPhotoURL())
+	assert.Equal(t, `https://vk.com/doc557404793_624170511`, msg.Docs()[0].Url)
+	assert.Equal(t, `https://psv4.userapi.com/voice.ogg`, msg.AudioMessages()[0].LinkOgg)
+	assert.Equal(t, `https://psv4.userapi.com/voice.mp3`, msg.AudioMessages()[0].LinkMp3)
+	assert.Equal(t, 9014, msg.Attachments[3].Sticker.StickerId)
+	assert.Equal(t, `vkbotserver`, msg.Attachments[4].Link.Title)
+	assert.Equal(t, `News`, msg.Attachments[5].Wall.Text)
+
+	assert.Equal(t, `forwarded`, msg.FwdMessages[0].Text)
+	assert.Equal(t, `nested`, msg.FwdMessages[0].FwdMessages[0].Text)
+	assert.Equal(t, int32(1234), msg.ReplyMessage.Id)
+	assert.Equal(t, 55.7558, msg.Geo.Coordinates.Latitude)
+	assert.Equal(t, `Moscow`, msg.Geo.Place.City)
+}
+
+func TestPhoto_Largest(t *testing.T) {
+	var (
+		tests = map[string]struct {
+			photo    Photo
+			expected string
+			found    bool
+		}{
+			`no sizes`: {
+				photo: Photo{},
+				found: false,
+			},
+			`by dimensions`: {
+				photo: Photo{Sizes: []PhotoSize{
+					{Type: `m`, Url: `m`, Width: 130, Height: 100},
+					{Type: `y`, Url: `y`, Width: 807, Height: 600},
+					{Type: `x`, Url: `x`, Width: 604, Height: 450},
+				}},
+				expected: `y`,
+				found:    true,
+			},
+			`by type without dimensions`: {
+				photo: Photo{Sizes: []PhotoSize{
+					{Type: `z`, Url: `z`},
+					{Type: `w`, Url: `w`},
+					{Type: `s`, Url: `s`},
+				}},
+				expected: `w`,
+				found:    true,
+			},
+		}
+	)
+
+	for testName, testCase := range tests {
+		size, found := testCase.photo.Largest()
+
+		assert.Equal(t, testCase.found, found, testName)
+		assert.Equal(t, testCase.expected, size.Url, testName)
+	}
+}
@@ -0,0 +1,263 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/mailru/easyjson"
+	"github.com/sepuka/vkbotserver/errors"
+)
+
+// Callback API event types, see https://dev.vk.com/api/community-events/json-schema
+const (
+	EventConfirmation     = `confirmation`
+	EventMessageNew       = `message_new`
+	EventMessageReply     = `message_reply`
+	EventMessageEdit      = `message_edit`
+	EventMessageEvent     = `message_event`
+	EventMessageAllow     = `message_allow`
+	EventMessageDeny      = `message_deny`
+	EventGroupJoin        = `group_join`
+	EventGroupLeave       = `group_leave`
+	EventWallPostNew      = `wall_post_new`
+	EventWallReplyNew     = `wall_reply_new`
+	EventLikeAdd          = `like_add`
+	EventLikeRemove       = `like_remove`
+	EventVkPayTransaction = `vkpay_transaction`
+	EventUserBlock        = `user_block`
+	EventUserUnblock      = `user_unblock`
+)
+
+type (
+	// MessageEvent is the object of message_event which comes when a user pushes a callback button
+	//easyjson:json
+	MessageEvent struct {
+		UserId                int             `json:"user_id"`
+		PeerId                int             `json:"peer_id"`
+		EventId               string          `json:"event_id"`
+		Payload               json.RawMessage `json:"payload"`
+		ConversationMessageId int             `json:"conversation_message_id"`
+	}
+
+	// MessageAllow is the object of message_allow, user allowed the group to send messages
+	//easyjson:json
+	MessageAllow struct {
+		UserId int    `json:"user_id"`
+		Key    string `json:"key"`
+	}
+
+	// MessageDeny is the object of message_deny, user denied the group to send messages
+	//easyjson:json
+	MessageDeny struct {
+		UserId int `json:"user_id"`
+	}
+
+	// GroupJoin is the object of group_join, join type is one of join, unsure, accepted, approved, request
+	//easyjson:json
+	GroupJoin struct {
+		UserId   int    `json:"user_id"`
+		JoinType string `json:"join_type"`
+	}
+
+	// GroupLeave is the object of group_leave, self is 1 if the user left by himself
+	//easyjson:json
+	GroupLeave struct {
+		UserId int `json:"user_id"`
+		Self   int `json:"self"`
+	}
+
+	// WallPost is the object of wall_post_new
+	//easyjson:json
+	WallPost struct {
+		Id           int          `json:"id"`
+		OwnerId      int          `json:"owner_id"`
+		FromId       int          `json:"from_id"`
+		CreatedBy    int          `json:"created_by"`
+		Date         int          `json:"date"`
+		Text         string       `json:"text"`
+		ReplyOwnerId int          `json:"reply_owner_id"`
+		ReplyPostId  int          `json:"reply_post_id"`
+		FriendsOnly  int          `json:"friends_only"`
+		PostType     string       `json:"post_type"`
+		SignerId     int          `json:"signer_id"`
+		MarkedAsAds  int          `json:"marked_as_ads"`
+		Attachments  []Attachment `json:"attachments"`
+	}
+
+	// WallComment is the object of wall_reply_new
+	//easyjson:json
+	WallComment struct {
+		Id             int          `json:"id"`
+		FromId         int          `json:"from_id"`
+		Date           int          `json:"date"`
+		Text           string       `json:"text"`
+		PostId         int          `json:"post_id"`
+		PostOwnerId    int          `json:"post_owner_id"`
+		OwnerId        int          `json:"owner_id"`
+		ReplyToUser    int          `json:"reply_to_user"`
+		ReplyToComment int          `json:"reply_to_comment"`
+		ParentsStack   []int        `json:"parents_stack"`
+		Attachments    []Attachment `json:"attachments"`
+	}
+
+	// Like is the object of like_add and like_remove
+	// object type is one of video, photo, post, comment, note, topic_comment, photo_comment, video_comment, market, market_comment
+	//easyjson:json
+	Like struct {
+		LikerId       int    `json:"liker_id"`
+		ObjectType    string `json:"object_type"`
+		ObjectOwnerId int    `json:"object_owner_id"`
+		ObjectId      int    `json:"object_id"`
+		ThreadReplyId int    `json:"thread_reply_id"`
+		PostId        int    `json:"post_id"`
+	}
+
+	// VkPayTransaction is the object of vkpay_transaction, amount is in thousandths of a ruble
+	//easyjson:json
+	VkPayTransaction struct {
+		FromId      int    `json:"from_id"`
+		Amount      int    `json:"amount"`
+		Description string `json:"description"`
+		Date        int    `json:"date"`
+	}
+
+	// UserBlock is the object of user_block
+	//easyjson:json
+	UserBlock struct {
+		AdminId     int    `json:"admin_id"`
+		UserId      int    `json:"user_id"`
+		UnblockDate int    `json:"unblock_date"`
+		Reason      int    `json:"reason"`
+		Comment     string `json:"comment"`
+	}
+
+	// UserUnblock is the object of user_unblock, by end date is 1 if the block was expired
+	//easyjson:json
+	UserUnblock struct {
+		AdminId   int `json:"admin_id"`
+		UserId    int `json:"user_id"`
+		ByEndDate int `json:"by_end_date"`
+	}
+)
+
+// MessageNew returns the new message
+func (v Request) MessageNew() (*Message, error) {
+	if v.Type != EventMessageNew {
+		return nil, v.wrongType(EventMessageNew)
+	}
+
+	return &v.Object.Message, nil
+}
+
+// MessageReply returns the message sent by the group
+func (v Request) MessageReply() (*Message, error) {
+	var msg = &Message{}
+
+	return msg, v.decode(EventMessageReply, msg)
+}
+
+// MessageEdit returns the edited message
+func (v Request) MessageEdit() (*Message, error) {
+	var msg = &Message{}
+
+	return msg, v.decode(EventMessageEdit, msg)
+}
+
+// MessageEvent returns the callback button event
+func (v Request) MessageEvent() (*MessageEvent, error) {
+	var event = &MessageEvent{}
+
+	return event, v.decode(EventMessageEvent, event)
+}
+
+// MessageAllow returns the messages subscription event
+func (v Request) MessageAllow() (*MessageAllow, error) {
+	var event = &MessageAllow{}
+
+	return event, v.decode(EventMessageAllow, event)
+}
+
+// MessageDeny returns the messages unsubscription event
+func (v Request) MessageDeny() (*MessageDeny, error) {
+	var event = &MessageDeny{}
+
+	return event, v.decode(EventMessageDeny, event)
+}
+
+// GroupJoin returns the group join event
+func (v Request) GroupJoin() (*GroupJoin, error) {
+	var event = &GroupJoin{}
+
+	return event, v.decode(EventGroupJoin, event)
+}
+
+// GroupLeave returns the group leave event
+func (v Request) GroupLeave() (*GroupLeave, error) {
+	var event = &GroupLeave{}
+
+	return event, v.decode(EventGroupLeave, event)
+}
+
+// WallPostNew returns the new wall post
+func (v Request) WallPostNew() (*WallPost, error) {
+	var post = &WallPost{}
+
+	return post, v.decode(EventWallPostNew, post)
+}
+
+// WallReplyNew returns the new wall comment
+func (v Request) WallReplyNew() (*WallComment, error) {
+	var comment = &WallComment{}
+
+	return comment, v.decode(EventWallReplyNew, comment)
+}
+
+// LikeAdd returns the like event
+func (v Request) LikeAdd() (*Like, error) {
+	var like = &Like{}
+
+	return like, v.decode(EventLikeAdd, like)
+}
+
+// LikeRemove returns the like removing event
+func (v Request) LikeRemove() (*Like, error) {
+	var like = &Like{}
+
+	return like, v.decode(EventLikeRemove, like)
+}
+
+// VkPayTransaction returns the VK Pay payment event
+func (v Request) VkPayTransaction() (*VkPayTransaction, error) {
+	var transaction = &VkPayTransaction{}
+
+	return transaction, v.decode(EventVkPayTransaction, transaction)
+}
+
+// UserBlock returns the user blocking event
+func (v Request) UserBlock() (*UserBlock, error) {
+	var event = &UserBlock{}
+
+	return event, v.decode(EventUserBlock, event)
+}
+
+// UserUnblock returns the user unblocking event
+func (v Request) UserUnblock() (*UserUnblock, error) {
+	var event = &UserUnblock{}
+
+	return event, v.decode(EventUserUnblock, event)
+}
+
+func (v Request) decode(eventType string, out easyjson.Unmarshaler) error {
+	if v.Type != eventType {
+		return v.wrongType(eventType)
+	}
+
+	if err := easyjson.Unmarshal(v.Object.raw, out); err != nil {
+		return errors.NewInvalidJsonError(fmt.Sprintf(`invalid %s object`, eventType), err)
+	}
+
+	return nil
+}
+
+func (v Request) wrongType(eventType string) error {
+	return errors.NewWrongEventTypeError(fmt.Sprintf(`%s event expected, %s given`, eventType, v.Type))
+}
@@ -0,0 +1,1144 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package domain
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain(in *jlexer.Lexer, out *WallPost) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.Id = int(in.Int())
+		case "owner_id":
+			out.OwnerId = int(in.Int())
+		case "from_id":
+			out.FromId = int(in.Int())
+		case "created_by":
+			out.CreatedBy = int(in.Int())
+		case "date":
+			out.Date = int(in.Int())
+		case "text":
+			out.Text = string(in.String())
+		case "reply_owner_id":
+			out.ReplyOwnerId = int(in.Int())
+		case "reply_post_id":
+			out.ReplyPostId = int(in.Int())
+		case "friends_only":
+			out.FriendsOnly = int(in.Int())
+		case "post_type":
+			out.PostType = string(in.String())
+		case "signer_id":
+			out.SignerId = int(in.Int())
+		case "marked_as_ads":
+			out.MarkedAsAds = int(in.Int())
+		case "attachments":
+			if in.IsNull() {
+				in.Skip()
+				out.Attachments = nil
+			} else {
+				in.Delim('[')
+				if out.Attachments == nil {
+					if !in.IsDelim(']') {
+						out.Attachments = make([]Attachment, 0, 0)
+					} else {
+						out.Attachments = []Attachment{}
+					}
+				} else {
+					out.Attachments = (out.Attachments)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 Attachment
+					(v1).UnmarshalEasyJSON(in)
+					out.Attachments = append(out.Attachments, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain(out *jwriter.Writer, in WallPost) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Id))
+	}
+	{
+		const prefix string = ",\"owner_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.OwnerId))
+	}
+	{
+		const prefix string = ",\"from_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.FromId))
+	}
+	{
+		const prefix string = ",\"created_by\":"
+		out.RawString(prefix)
+		out.Int(int(in.CreatedBy))
+	}
+	{
+		const prefix string = ",\"date\":"
+		out.RawString(prefix)
+		out.Int(int(in.Date))
+	}
+	{
+		const prefix string = ",\"text\":"
+		out.RawString(prefix)
+		out.String(string(in.Text))
+	}
+	{
+		const prefix string = ",\"reply_owner_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.ReplyOwnerId))
+	}
+	{
+		const prefix string = ",\"reply_post_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.ReplyPostId))
+	}
+	{
+		const prefix string = ",\"friends_only\":"
+		out.RawString(prefix)
+		out.Int(int(in.FriendsOnly))
+	}
+	{
+		const prefix string = ",\"post_type\":"
+		out.RawString(prefix)
+		out.String(string(in.PostType))
+	}
+	{
+		const prefix string = ",\"signer_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.SignerId))
+	}
+	{
+		const prefix string = ",\"marked_as_ads\":"
+		out.RawString(prefix)
+		out.Int(int(in.MarkedAsAds))
+	}
+	{
+		const prefix string = ",\"attachments\":"
+		out.RawString(prefix)
+		if in.Attachments == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Attachments {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				(v3).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v WallPost) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v WallPost) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *WallPost) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *WallPost) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain(l, v)
+}
+func easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain1(in *jlexer.Lexer, out *WallComment) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.Id = int(in.Int())
+		case "from_id":
+			out.FromId = int(in.Int())
+		case "date":
+			out.Date = int(in.Int())
+		case "text":
+			out.Text = string(in.String())
+		case "post_id":
+			out.PostId = int(in.Int())
+		case "post_owner_id":
+			out.PostOwnerId = int(in.Int())
+		case "owner_id":
+			out.OwnerId = int(in.Int())
+		case "reply_to_user":
+			out.ReplyToUser = int(in.Int())
+		case "reply_to_comment":
+			out.ReplyToComment = int(in.Int())
+		case "parents_stack":
+			if in.IsNull() {
+				in.Skip()
+				out.ParentsStack = nil
+			} else {
+				in.Delim('[')
+				if out.ParentsStack == nil {
+					if !in.IsDelim(']') {
+						out.ParentsStack = make([]int, 0, 8)
+					} else {
+						out.ParentsStack = []int{}
+					}
+				} else {
+					out.ParentsStack = (out.ParentsStack)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 int
+					v4 = int(in.Int())
+					out.ParentsStack = append(out.ParentsStack, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "attachments":
+			if in.IsNull() {
+				in.Skip()
+				out.Attachments = nil
+			} else {
+				in.Delim('[')
+				if out.Attachments == nil {
+					if !in.IsDelim(']') {
+						out.Attachments = make([]Attachment, 0, 0)
+					} else {
+						out.Attachments = []Attachment{}
+					}
+				} else {
+					out.Attachments = (out.Attachments)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v5 Attachment
+					(v5).UnmarshalEasyJSON(in)
+					out.Attachments = append(out.Attachments, v5)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain1(out *jwriter.Writer, in WallComment) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Id))
+	}
+	{
+		const prefix string = ",\"from_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.FromId))
+	}
+	{
+		const prefix string = ",\"date\":"
+		out.RawString(prefix)
+		out.Int(int(in.Date))
+	}
+	{
+		const prefix string = ",\"text\":"
+		out.RawString(prefix)
+		out.String(string(in.Text))
+	}
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.PostId))
+	}
+	{
+		const prefix string = ",\"post_owner_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.PostOwnerId))
+	}
+	{
+		const prefix string = ",\"owner_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.OwnerId))
+	}
+	{
+		const prefix string = ",\"reply_to_user\":"
+		out.RawString(prefix)
+		out.Int(int(in.ReplyToUser))
+	}
+	{
+		const prefix string = ",\"reply_to_comment\":"
+		out.RawString(prefix)
+		out.Int(int(in.ReplyToComment))
+	}
+	{
+		const prefix string = ",\"parents_stack\":"
+		out.RawString(prefix)
+		if in.ParentsStack == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v6, v7 := range in.ParentsStack {
+				if v6 > 0 {
+					out.RawByte(',')
+				}
+				out.Int(int(v7))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"attachments\":"
+		out.RawString(prefix)
+		if in.Attachments == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v8, v9 := range in.Attachments {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				(v9).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v WallComment) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v WallComment) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *WallComment) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *WallComment) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain1(l, v)
+}
+func easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain2(in *jlexer.Lexer, out *VkPayTransaction) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "from_id":
+			out.FromId = int(in.Int())
+		case "amount":
+			out.Amount = int(in.Int())
+		case "description":
+			out.Description = string(in.String())
+		case "date":
+			out.Date = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain2(out *jwriter.Writer, in VkPayTransaction) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"from_id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.FromId))
+	}
+	{
+		const prefix string = ",\"amount\":"
+		out.RawString(prefix)
+		out.Int(int(in.Amount))
+	}
+	{
+		const prefix string = ",\"description\":"
+		out.RawString(prefix)
+		out.String(string(in.Description))
+	}
+	{
+		const prefix string = ",\"date\":"
+		out.RawString(prefix)
+		out.Int(int(in.Date))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v VkPayTransaction) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v VkPayTransaction) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *VkPayTransaction) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *VkPayTransaction) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain2(l, v)
+}
+func easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain3(in *jlexer.Lexer, out *UserUnblock) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "admin_id":
+			out.AdminId = int(in.Int())
+		case "user_id":
+			out.UserId = int(in.Int())
+		case "by_end_date":
+			out.ByEndDate = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain3(out *jwriter.Writer, in UserUnblock) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"admin_id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.AdminId))
+	}
+	{
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.UserId))
+	}
+	{
+		const prefix string = ",\"by_end_date\":"
+		out.RawString(prefix)
+		out.Int(int(in.ByEndDate))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v UserUnblock) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserUnblock) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *UserUnblock) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserUnblock) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain3(l, v)
+}
+func easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain4(in *jlexer.Lexer, out *UserBlock) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "admin_id":
+			out.AdminId = int(in.Int())
+		case "user_id":
+			out.UserId = int(in.Int())
+		case "unblock_date":
+			out.UnblockDate = int(in.Int())
+		case "reason":
+			out.Reason = int(in.Int())
+		case "comment":
+			out.Comment = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain4(out *jwriter.Writer, in UserBlock) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"admin_id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.AdminId))
+	}
+	{
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.UserId))
+	}
+	{
+		const prefix string = ",\"unblock_date\":"
+		out.RawString(prefix)
+		out.Int(int(in.UnblockDate))
+	}
+	{
+		const prefix string = ",\"reason\":"
+		out.RawString(prefix)
+		out.Int(int(in.Reason))
+	}
+	{
+		const prefix string = ",\"comment\":"
+		out.RawString(prefix)
+		out.String(string(in.Comment))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v UserBlock) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain4(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserBlock) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain4(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *UserBlock) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain4(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserBlock) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain4(l, v)
+}
+func easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain5(in *jlexer.Lexer, out *MessageEvent) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "user_id":
+			out.UserId = int(in.Int())
+		case "peer_id":
+			out.PeerId = int(in.Int())
+		case "event_id":
+			out.EventId = string(in.String())
+		case "payload":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Payload).UnmarshalJSON(data))
+			}
+		case "conversation_message_id":
+			out.ConversationMessageId = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain5(out *jwriter.Writer, in MessageEvent) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.UserId))
+	}
+	{
+		const prefix string = ",\"peer_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.PeerId))
+	}
+	{
+		const prefix string = ",\"event_id\":"
+		out.RawString(prefix)
+		out.String(string(in.EventId))
+	}
+	{
+		const prefix string = ",\"payload\":"
+		out.RawString(prefix)
+		out.Raw((in.Payload).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"conversation_message_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.ConversationMessageId))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MessageEvent) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain5(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MessageEvent) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain5(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MessageEvent) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain5(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MessageEvent) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain5(l, v)
+}
+func easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain6(in *jlexer.Lexer, out *MessageDeny) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "user_id":
+			out.UserId = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain6(out *jwriter.Writer, in MessageDeny) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.UserId))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MessageDeny) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain6(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MessageDeny) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain6(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MessageDeny) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain6(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MessageDeny) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain6(l, v)
+}
+func easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain7(in *jlexer.Lexer, out *MessageAllow) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "user_id":
+			out.UserId = int(in.Int())
+		case "key":
+			out.Key = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain7(out *jwriter.Writer, in MessageAllow) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.UserId))
+	}
+	{
+		const prefix string = ",\"key\":"
+		out.RawString(prefix)
+		out.String(string(in.Key))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MessageAllow) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain7(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MessageAllow) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain7(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MessageAllow) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain7(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MessageAllow) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain7(l, v)
+}
+func easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain8(in *jlexer.Lexer, out *Like) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "liker_id":
+			out.LikerId = int(in.Int())
+		case "object_type":
+			out.ObjectType = string(in.String())
+		case "object_owner_id":
+			out.ObjectOwnerId = int(in.Int())
+		case "object_id":
+			out.ObjectId = int(in.Int())
+		case "thread_reply_id":
+			out.ThreadReplyId = int(in.Int())
+		case "post_id":
+			out.PostId = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain8(out *jwriter.Writer, in Like) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"liker_id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.LikerId))
+	}
+	{
+		const prefix string = ",\"object_type\":"
+		out.RawString(prefix)
+		out.String(string(in.ObjectType))
+	}
+	{
+		const prefix string = ",\"object_owner_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.ObjectOwnerId))
+	}
+	{
+		const prefix string = ",\"object_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.ObjectId))
+	}
+	{
+		const prefix string = ",\"thread_reply_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.ThreadReplyId))
+	}
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix)
+		out.Int(int(in.PostId))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Like) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain8(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Like) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain8(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Like) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain8(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Like) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain8(l, v)
+}
+func easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain9(in *jlexer.Lexer, out *GroupLeave) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "user_id":
+			out.UserId = int(in.Int())
+		case "self":
+			out.Self = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain9(out *jwriter.Writer, in GroupLeave) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.UserId))
+	}
+	{
+		const prefix string = ",\"self\":"
+		out.RawString(prefix)
+		out.Int(int(in.Self))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GroupLeave) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain9(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GroupLeave) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain9(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GroupLeave) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain9(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GroupLeave) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain9(l, v)
+}
+func easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain10(in *jlexer.Lexer, out *GroupJoin) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "user_id":
+			out.UserId = int(in.Int())
+		case "join_type":
+			out.JoinType = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain10(out *jwriter.Writer, in GroupJoin) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.UserId))
+	}
+	{
+		const prefix string = ",\"join_type\":"
+		out.RawString(prefix)
+		out.String(string(in.JoinType))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GroupJoin) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain10(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GroupJoin) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF642ad3eEncodeGithubComSepukaVkbotserverDomain10(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GroupJoin) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain10(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GroupJoin) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF642ad3eDecodeGithubComSepukaVkbotserverDomain10(l, v)
+}
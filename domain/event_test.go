@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"github.com/mailru/easyjson"
+	"github.com/sepuka/vkbotserver/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRequest_TypedObjects(t *testing.T) {
+	var (
+		decode = func(payload string) Request {
+			var req = Request{}
+			assert.Nil(t, easyjson.Unmarshal([]byte(payload), &req), payload)
+
+			return req
+		}
+		tests = map[string]struct {
+			payload  string
+			object   func(req Request) (interface{}, error)
+			expected interface{}
+		}{
+			`message_reply`: {
+				payload: `{"group_id":199999999,"type":"message_reply","event_id":"3b3c1a0d6e8a7b2f9a1c2d3e4f5a6b7c8d9e0f1a","v":"5.131","object":{"date":1653041018,"from_id":-199999999,"id":1234,"out":1,"attachments":[],"conversation_message_id":567,"fwd_messages":[],"important":false,"is_hidden":false,"peer_id":557404793,"random_id":5483270417012340000,"text":"Hello world!"}}`,
+				object: func(req Request) (interface{}, error) {
+					msg, err := req.MessageReply()
+					return msg.Text, err
+				},
+				expected: `Hello world!`,
+			},
+			`message_edit`: {
+				payload: `{"group_id":199999999,"type":"message_edit","event_id":"a1","v":"5.131","object":{"date":1653041018,"from_id":-199999999,"id":1234,"out":1,"conversation_message_id":567,"peer_id":557404793,"text":"Edited","update_time":1653041100}}`,
+				object: func(req Request) (interface{}, error) {
+					msg, err := req.MessageEdit()
+					return msg.ConversationMessageId, err
+				},
+				expected: int32(567),
+			},
+			`message_event`: {
+				payload: `{"group_id":199999999,"type":"message_event","event_id":"a2","v":"5.131","object":{"user_id":557404793,"peer_id":557404793,"event_id":"e6ba7d6a3c3f","payload":{"command":"start"},"conversation_message_id":568}}`,
+				object: func(req Request) (interface{}, error) {
+					event, err := req.MessageEvent()
+					return []interface{}{event.UserId, event.EventId, string(event.Payload), event.ConversationMessageId}, err
+				},
+				expected: []interface{}{557404793, `e6ba7d6a3c3f`, `{"command":"start"}`, 568},
+			},
+			`message_allow`: {
+				payload: `{"group_id":199999999,"type":"message_allow","event_id":"a3","v":"5.131","object":{"user_id":557404793,"key":"subscribe_key"}}`,
+				object: func(req Request) (interface{}, error) {
+					return req.MessageAllow()
+				},
+				expected: &MessageAllow{UserId: 557404793, Key: `subscribe_key`},
+			},
+			`message_deny`: {
+				payload: `{"group_id":199999999,"type":"message_deny","event_id":"a4","v":"5.131","object":{"user_id":557404793}}`,
+				object: func(req Request) (interface{}, error) {
+					return req.MessageDeny()
+				},
+				expected: &MessageDeny{UserId: 557404793},
+			},
+			`group_join`: {
+				payload: `{"group_id":199999999,"type":"group_join","event_id":"a5","v":"5.131","object":{"user_id":557404793,"join_type":"join"}}`,
+				object: func(req Request) (interface{}, error) {
+					return req.GroupJoin()
+				},
+				expected: &GroupJoin{UserId: 557404793, JoinType: `join`},
+			},
+			`group_leave`: {
+				payload: `{"group_id":199999999,"type":"group_leave","event_id":"a6","v":"5.131","object":{"user_id":557404793,"self":1}}`,
+				object: func(req Request) (interface{}, error) {
+					return req.GroupLeave()
+				},
+				expected: &GroupLeave{UserId: 557404793, Self: 1},
+			},
+			`wall_post_new`: {
+				payload: `{"group_id":199999999,"type":"wall_post_new","event_id":"a7","v":"5.131","object":{"id":42,"from_id":-199999999,"owner_id":-199999999,"date":1653041018,"marked_as_ads":0,"post_type":"post","text":"News","can_edit":1,"created_by":557404793,"can_delete":1,"comments":{"count":0},"is_favorite":false}}`,
+				object: func(req Request) (interface{}, error) {
+					return req.WallPostNew()
+				},
+				expected: &WallPost{Id: 42, FromId: -199999999, OwnerId: -199999999, Date: 1653041018, PostType: `post`, Text: `News`, CreatedBy: 557404793},
+			},
+			`wall_reply_new`: {
+				payload: `{"group_id":199999999,"type":"wall_reply_new","event_id":"a8","v":"5.131","object":{"id":43,"from_id":557404793,"post_id":42,"owner_id":-199999999,"parents_stack":[],"date":1653041100,"text":"Nice","thread":{"count":0},"post_owner_id":-199999999}}`,
+				object: func(req Request) (interface{}, error) {
+					return req.WallReplyNew()
+				},
+				expected: &WallComment{Id: 43, FromId: 557404793, PostId: 42, OwnerId: -199999999, ParentsStack: []int{}, Date: 1653041100, Text: `Nice`, PostOwnerId: -199999999},
+			},
+			`like_add`: {
+				payload: `{"group_id":199999999,"type":"like_add","event_id":"a9","v":"5.131","object":{"liker_id":557404793,"object_type":"post","object_owner_id":-199999999,"object_id":42,"thread_reply_id":0,"post_id":0}}`,
+				object: func(req Request) (interface{}, error) {
+					return req.LikeAdd()
+				},
+				expected: &Like{LikerId: 557404793, ObjectType: `post`, ObjectOwnerId: -199999999, ObjectId: 42},
+			},
+			`vkpay_transaction`: {
+				payload: `{"group_id":199999999,"type":"vkpay_transaction","event_id":"b1","v":"5.131","object":{"from_id":557404793,"amount":100000,"description":"donation","date":1653041018}}`,
+				object: func(req Request) (interface{}, error) {
+					return req.VkPayTransaction()
+				},
+				expected: &VkPayTransaction{FromId: 557404793, Amount: 100000, Description: `donation`, Date: 1653041018},
+			},
+			`user_block`: {
+				payload: `{"group_id":199999999,"type":"user_block","event_id":"b2","v":"5.131","object":{"admin_id":1,"user_id":557404793,"unblock_date":0,"reason":1,"comment":"spam"}}`,
+				object: func(req Request) (interface{}, error) {
+					return req.UserBlock()
+				},
+				expected: &UserBlock{AdminId: 1, UserId: 557404793, Reason: 1, Comment: `spam`},
+			},
+		}
+	)
+
+	for testName, testCase := range tests {
+		var actual, err = testCase.object(decode(testCase.payload))
+
+		assert.Nil(t, err, testName)
+		assert.Equal(t, testCase.expected, actual, testName)
+	}
+}
+
+func TestRequest_MessageNew(t *testing.T) {
+	const payload = `{"group_id":199999999,"type":"message_new","event_id":"b3","v":"5.131","object":{"message":{"date":1653041018,"from_id":557404793,"id":0,"out":0,"conversation_message_id":569,"important":false,"is_hidden":false,"peer_id":557404793,"random_id":0,"text":"start"},"client_info":{"button_actions":["text","vkpay","open_app","location","open_link","callback"],"keyboard":true,"inline_keyboard":true,"carousel":true,"lang_id":0}}}`
+
+	var req = Request{}
+
+	assert.Nil(t, easyjson.Unmarshal([]byte(payload), &req))
+
+	msg, err := req.MessageNew()
+	assert.Nil(t, err)
+	assert.Equal(t, `start`, msg.Text)
+	assert.True(t, req.Object.ClientInfo.IsKnown())
+	assert.True(t, req.Object.ClientInfo.InlineKeyboard)
+	assert.True(t, req.Object.ClientInfo.Carousel)
+	assert.True(t, req.Object.ClientInfo.SupportsAction(`callback`))
+	assert.False(t, req.Object.ClientInfo.SupportsAction(`intent_subscribe`))
+
+	_, err = req.GroupJoin()
+	assert.ErrorIs(t, err, errors.WrongEventType)
+}
+
+func TestRequest_MarshalKeepsObject(t *testing.T) {
+	const payload = `{"type":"group_join","object":{"user_id":557404793,"join_type":"join"},"group_id":1,"event_id":"b4","secret":""}`
+
+	var (
+		req     = Request{}
+		decoded = Request{}
+	)
+
+	assert.Nil(t, easyjson.Unmarshal([]byte(payload), &req))
+
+	data, err := easyjson.Marshal(req)
+	assert.Nil(t, err)
+	assert.Nil(t, easyjson.Unmarshal(data, &decoded))
+
+	join, err := decoded.GroupJoin()
+	assert.Nil(t, err)
+	assert.Equal(t, 557404793, join.UserId)
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "encoding/json"
+
+const (
+	// LongPollFailedTs means that events history is outdated or lost partially, ts must be refreshed
+	LongPollFailedTs = 1
+	// LongPollFailedKey means that the key is expired, a new one must be got
+	LongPollFailedKey = 2
+	// LongPollFailedInfo means that all the server info is lost, the key and ts must be got again
+	LongPollFailedInfo = 3
+)
+
+type (
+	// LongPollServer describes the server which long poll requests should be sent to
+	LongPollServer struct {
+		Key    string      `json:"key"`
+		Server string      `json:"server"`
+		Ts     json.Number `json:"ts"`
+	}
+
+	// LongPollServerResponse is the groups.getLongPollServer answer
+	//easyjson:json
+	LongPollServerResponse struct {
+		Response LongPollServer `json:"response"`
+		Error    Error          `json:"error"`
+	}
+
+	// LongPollUpdates is the answer of the long poll server, each update has the same format as a Callback API event
+	//easyjson:json
+	LongPollUpdates struct {
+		Ts      json.Number `json:"ts"`
+		Updates []Request   `json:"updates"`
+		Failed  int         `json:"failed"`
+	}
+)
@@ -0,0 +1,260 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package domain
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson1d1fc145DecodeGithubComSepukaVkbotserverDomain(in *jlexer.Lexer, out *LongPollUpdates) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "ts":
+			out.Ts = in.JsonNumber()
+		case "updates":
+			if in.IsNull() {
+				in.Skip()
+				out.Updates = nil
+			} else {
+				in.Delim('[')
+				if out.Updates == nil {
+					if !in.IsDelim(']') {
+						out.Updates = make([]Request, 0, 0)
+					} else {
+						out.Updates = []Request{}
+					}
+				} else {
+					out.Updates = (out.Updates)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 Request
+					(v1).UnmarshalEasyJSON(in)
+					out.Updates = append(out.Updates, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "failed":
+			out.Failed = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson1d1fc145EncodeGithubComSepukaVkbotserverDomain(out *jwriter.Writer, in LongPollUpdates) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"ts\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Ts))
+	}
+	{
+		const prefix string = ",\"updates\":"
+		out.RawString(prefix)
+		if in.Updates == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Updates {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				(v3).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"failed\":"
+		out.RawString(prefix)
+		out.Int(int(in.Failed))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v LongPollUpdates) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson1d1fc145EncodeGithubComSepukaVkbotserverDomain(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v LongPollUpdates) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson1d1fc145EncodeGithubComSepukaVkbotserverDomain(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *LongPollUpdates) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson1d1fc145DecodeGithubComSepukaVkbotserverDomain(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *LongPollUpdates) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson1d1fc145DecodeGithubComSepukaVkbotserverDomain(l, v)
+}
+func easyjson1d1fc145DecodeGithubComSepukaVkbotserverDomain1(in *jlexer.Lexer, out *LongPollServerResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "response":
+			easyjson1d1fc145DecodeGithubComSepukaVkbotserverDomain2(in, &out.Response)
+		case "error":
+			(out.Error).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson1d1fc145EncodeGithubComSepukaVkbotserverDomain1(out *jwriter.Writer, in LongPollServerResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"response\":"
+		out.RawString(prefix[1:])
+		easyjson1d1fc145EncodeGithubComSepukaVkbotserverDomain2(out, in.Response)
+	}
+	{
+		const prefix string = ",\"error\":"
+		out.RawString(prefix)
+		(in.Error).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v LongPollServerResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson1d1fc145EncodeGithubComSepukaVkbotserverDomain1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v LongPollServerResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson1d1fc145EncodeGithubComSepukaVkbotserverDomain1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *LongPollServerResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson1d1fc145DecodeGithubComSepukaVkbotserverDomain1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *LongPollServerResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson1d1fc145DecodeGithubComSepukaVkbotserverDomain1(l, v)
+}
+func easyjson1d1fc145DecodeGithubComSepukaVkbotserverDomain2(in *jlexer.Lexer, out *LongPollServer) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "key":
+			out.Key = string(in.String())
+		case "server":
+			out.Server = string(in.String())
+		case "ts":
+			out.Ts = in.JsonNumber()
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson1d1fc145EncodeGithubComSepukaVkbotserverDomain2(out *jwriter.Writer, in LongPollServer) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"key\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Key))
+	}
+	{
+		const prefix string = ",\"server\":"
+		out.RawString(prefix)
+		out.String(string(in.Server))
+	}
+	{
+		const prefix string = ",\"ts\":"
+		out.RawString(prefix)
+		out.String(string(in.Ts))
+	}
+	out.RawByte('}')
+}
@@ -1,44 +1,70 @@
 package domain
 
+import (
+	"github.com/mailru/easyjson"
+	"github.com/mailru/easyjson/jlexer"
+	"github.com/mailru/easyjson/jwriter"
+)
+
 // Message is the main message container
+//
+//easyjson:json
 type Message struct {
-	Id                    int32  `json:"id"`
-	Date                  int32  `json:"date"`
-	FromId                int32  `json:"from_id"`
-	PeerId                int32  `json:"peer_id"`
-	Out                   int32  `json:"out"`
-	Text                  string `json:"text"`
-	ConversationMessageId int32  `json:"conversation_message_id"`
-	FwdMessages           []int  `json:"fwd_messages"`
-	Important             bool   `json:"important"`
-	RandomId              int32  `json:"random_id"`
-	Attachments           []int  `json:"attachments"`
-	IsHidden              bool   `json:"is_hidden"`
-	Payload               string `json:"payload"`
-}
-
-// Some client info (unused yet)
+	Id                    int32        `json:"id"`
+	Date                  int32        `json:"date"`
+	FromId                int32        `json:"from_id"`
+	PeerId                int32        `json:"peer_id"`
+	Out                   int32        `json:"out"`
+	Text                  string       `json:"text"`
+	ConversationMessageId int32        `json:"conversation_message_id"`
+	FwdMessages           []Message    `json:"fwd_messages"`
+	ReplyMessage          *Message     `json:"reply_message"`
+	Important             bool         `json:"important"`
+	RandomId              int64        `json:"random_id"`
+	Attachments           []Attachment `json:"attachments"`
+	Geo                   *Geo         `json:"geo"`
+	IsHidden              bool         `json:"is_hidden"`
+	Payload               string       `json:"payload"`
+}
+
+// ClientInfo describes features supported by the user's client
 type ClientInfo struct {
-	ButtonActions  []string
-	Keyboard       bool
-	InlineKeyboard bool
-	LandId         int8
+	ButtonActions  []string `json:"button_actions"`
+	Keyboard       bool     `json:"keyboard"`
+	InlineKeyboard bool     `json:"inline_keyboard"`
+	Carousel       bool     `json:"carousel"`
+	// LandId is the client language id, the name is kept for compatibility
+	//
+	// Deprecated: use LangId
+	LandId int8 `json:"lang_id"`
+}
+
+// LangId returns the language id of the client
+func (c ClientInfo) LangId() int {
+	return int(c.LandId)
 }
 
 // Object of the message
+// the original JSON is kept in order to decode objects of other events by typed accessors like Request.GroupJoin
 type Object struct {
-	Message    Message `json:"message"`
-	ClientInfo ClientInfo
+	Message    Message    `json:"message"`
+	ClientInfo ClientInfo `json:"client_info"`
+	raw        []byte
 }
 
+// object is decoded by generated code, Object wraps it keeping the original JSON
+//
+//easyjson:json
+type object Object
+
 //easyjson:json
 type Request struct {
-	Type    string `json:"type"`
-	Object  Object `json:"object"`
-	GroupId int32  `json:"group_id"`
-	EventId string `json:"event_id"`
-	Secret  string `json:"secret"`
-	Context interface{}
+	Type    string      `json:"type"`
+	Object  Object      `json:"object"`
+	GroupId int32       `json:"group_id"`
+	EventId string      `json:"event_id"`
+	Secret  string      `json:"secret"`
+	Context interface{} `json:"-"`
 }
 
 // detects which type of requests you've got
@@ -46,3 +72,60 @@ type Request struct {
 func (v Request) IsKeyboardButton() bool {
 	return v.Object.Message.Payload != ``
 }
+
+// IsKnown tells whether the client info came with the event, e.g. it's absent in events other than message_new
+func (c ClientInfo) IsKnown() bool {
+	return c.ButtonActions != nil || c.Keyboard || c.InlineKeyboard || c.Carousel
+}
+
+// SupportsAction tells whether the client supports buttons with the action type like text or callback
+func (c ClientInfo) SupportsAction(action string) bool {
+	for _, supported := range c.ButtonActions {
+		if supported == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (o *Object) UnmarshalEasyJSON(in *jlexer.Lexer) {
+	var (
+		raw     = in.Raw()
+		decoded object
+	)
+
+	if !in.Ok() {
+		return
+	}
+
+	if err := easyjson.Unmarshal(raw, &decoded); err != nil {
+		in.AddError(err)
+		return
+	}
+
+	*o = Object(decoded)
+	o.raw = append([]byte(nil), raw...)
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+// the original JSON is written if the object was decoded
+func (o Object) MarshalEasyJSON(out *jwriter.Writer) {
+	if len(o.raw) > 0 {
+		out.Raw(o.raw, nil)
+		return
+	}
+
+	object(o).MarshalEasyJSON(out)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (o *Object) UnmarshalJSON(data []byte) error {
+	return easyjson.Unmarshal(data, o)
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (o Object) MarshalJSON() ([]byte, error) {
+	return easyjson.Marshal(o)
+}
@@ -17,7 +17,7 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain(in *jlexer.Lexer, out *Request) {
+func easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain(in *jlexer.Lexer, out *object) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -36,16 +36,10 @@ func easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "type":
-			out.Type = string(in.String())
-		case "object":
-			easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain1(in, &out.Object)
-		case "group_id":
-			out.GroupId = int32(in.Int32())
-		case "event_id":
-			out.EventId = string(in.String())
-		case "secret":
-			out.Secret = string(in.String())
+		case "message":
+			(out.Message).UnmarshalEasyJSON(in)
+		case "client_info":
+			easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain1(in, &out.ClientInfo)
 		default:
 			in.SkipRecursive()
 		}
@@ -56,62 +50,47 @@ func easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain(out *jwriter.Writer, in Request) {
+func easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain(out *jwriter.Writer, in object) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"type\":"
+		const prefix string = ",\"message\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Type))
-	}
-	{
-		const prefix string = ",\"object\":"
-		out.RawString(prefix)
-		easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain1(out, in.Object)
-	}
-	{
-		const prefix string = ",\"group_id\":"
-		out.RawString(prefix)
-		out.Int32(int32(in.GroupId))
+		(in.Message).MarshalEasyJSON(out)
 	}
 	{
-		const prefix string = ",\"event_id\":"
+		const prefix string = ",\"client_info\":"
 		out.RawString(prefix)
-		out.String(string(in.EventId))
-	}
-	{
-		const prefix string = ",\"secret\":"
-		out.RawString(prefix)
-		out.String(string(in.Secret))
+		easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain1(out, in.ClientInfo)
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Request) MarshalJSON() ([]byte, error) {
+func (v object) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Request) MarshalEasyJSON(w *jwriter.Writer) {
+func (v object) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Request) UnmarshalJSON(data []byte) error {
+func (v *object) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Request) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *object) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain(l, v)
 }
-func easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain1(in *jlexer.Lexer, out *Object) {
+func easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain1(in *jlexer.Lexer, out *ClientInfo) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -130,56 +109,7 @@ func easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain1(in *jlexer.Lexer, o
 			continue
 		}
 		switch key {
-		case "message":
-			easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain2(in, &out.Message)
-		case "ClientInfo":
-			easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain3(in, &out.ClientInfo)
-		default:
-			in.SkipRecursive()
-		}
-		in.WantComma()
-	}
-	in.Delim('}')
-	if isTopLevel {
-		in.Consumed()
-	}
-}
-func easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain1(out *jwriter.Writer, in Object) {
-	out.RawByte('{')
-	first := true
-	_ = first
-	{
-		const prefix string = ",\"message\":"
-		out.RawString(prefix[1:])
-		easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain2(out, in.Message)
-	}
-	{
-		const prefix string = ",\"ClientInfo\":"
-		out.RawString(prefix)
-		easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain3(out, in.ClientInfo)
-	}
-	out.RawByte('}')
-}
-func easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain3(in *jlexer.Lexer, out *ClientInfo) {
-	isTopLevel := in.IsStart()
-	if in.IsNull() {
-		if isTopLevel {
-			in.Consumed()
-		}
-		in.Skip()
-		return
-	}
-	in.Delim('{')
-	for !in.IsDelim('}') {
-		key := in.UnsafeFieldName(false)
-		in.WantColon()
-		if in.IsNull() {
-			in.Skip()
-			in.WantComma()
-			continue
-		}
-		switch key {
-		case "ButtonActions":
+		case "button_actions":
 			if in.IsNull() {
 				in.Skip()
 				out.ButtonActions = nil
@@ -202,11 +132,13 @@ func easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain3(in *jlexer.Lexer, o
 				}
 				in.Delim(']')
 			}
-		case "Keyboard":
+		case "keyboard":
 			out.Keyboard = bool(in.Bool())
-		case "InlineKeyboard":
+		case "inline_keyboard":
 			out.InlineKeyboard = bool(in.Bool())
-		case "LandId":
+		case "carousel":
+			out.Carousel = bool(in.Bool())
+		case "lang_id":
 			out.LandId = int8(in.Int8())
 		default:
 			in.SkipRecursive()
@@ -218,12 +150,12 @@ func easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain3(in *jlexer.Lexer, o
 		in.Consumed()
 	}
 }
-func easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain3(out *jwriter.Writer, in ClientInfo) {
+func easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain1(out *jwriter.Writer, in ClientInfo) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"ButtonActions\":"
+		const prefix string = ",\"button_actions\":"
 		out.RawString(prefix[1:])
 		if in.ButtonActions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
 			out.RawString("null")
@@ -239,23 +171,122 @@ func easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain3(out *jwriter.Writer
 		}
 	}
 	{
-		const prefix string = ",\"Keyboard\":"
+		const prefix string = ",\"keyboard\":"
 		out.RawString(prefix)
 		out.Bool(bool(in.Keyboard))
 	}
 	{
-		const prefix string = ",\"InlineKeyboard\":"
+		const prefix string = ",\"inline_keyboard\":"
 		out.RawString(prefix)
 		out.Bool(bool(in.InlineKeyboard))
 	}
 	{
-		const prefix string = ",\"LandId\":"
+		const prefix string = ",\"carousel\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Carousel))
+	}
+	{
+		const prefix string = ",\"lang_id\":"
 		out.RawString(prefix)
 		out.Int8(int8(in.LandId))
 	}
 	out.RawByte('}')
 }
-func easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain2(in *jlexer.Lexer, out *Message) {
+func easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain2(in *jlexer.Lexer, out *Request) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "type":
+			out.Type = string(in.String())
+		case "object":
+			(out.Object).UnmarshalEasyJSON(in)
+		case "group_id":
+			out.GroupId = int32(in.Int32())
+		case "event_id":
+			out.EventId = string(in.String())
+		case "secret":
+			out.Secret = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain2(out *jwriter.Writer, in Request) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"object\":"
+		out.RawString(prefix)
+		(in.Object).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"group_id\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.GroupId))
+	}
+	{
+		const prefix string = ",\"event_id\":"
+		out.RawString(prefix)
+		out.String(string(in.EventId))
+	}
+	{
+		const prefix string = ",\"secret\":"
+		out.RawString(prefix)
+		out.String(string(in.Secret))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Request) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Request) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Request) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Request) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain2(l, v)
+}
+func easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain3(in *jlexer.Lexer, out *Message) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -296,25 +327,35 @@ func easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain2(in *jlexer.Lexer, o
 				in.Delim('[')
 				if out.FwdMessages == nil {
 					if !in.IsDelim(']') {
-						out.FwdMessages = make([]int, 0, 8)
+						out.FwdMessages = make([]Message, 0, 0)
 					} else {
-						out.FwdMessages = []int{}
+						out.FwdMessages = []Message{}
 					}
 				} else {
 					out.FwdMessages = (out.FwdMessages)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v4 int
-					v4 = int(in.Int())
+					var v4 Message
+					(v4).UnmarshalEasyJSON(in)
 					out.FwdMessages = append(out.FwdMessages, v4)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
+		case "reply_message":
+			if in.IsNull() {
+				in.Skip()
+				out.ReplyMessage = nil
+			} else {
+				if out.ReplyMessage == nil {
+					out.ReplyMessage = new(Message)
+				}
+				(*out.ReplyMessage).UnmarshalEasyJSON(in)
+			}
 		case "important":
 			out.Important = bool(in.Bool())
 		case "random_id":
-			out.RandomId = int32(in.Int32())
+			out.RandomId = int64(in.Int64())
 		case "attachments":
 			if in.IsNull() {
 				in.Skip()
@@ -323,21 +364,31 @@ func easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain2(in *jlexer.Lexer, o
 				in.Delim('[')
 				if out.Attachments == nil {
 					if !in.IsDelim(']') {
-						out.Attachments = make([]int, 0, 8)
+						out.Attachments = make([]Attachment, 0, 0)
 					} else {
-						out.Attachments = []int{}
+						out.Attachments = []Attachment{}
 					}
 				} else {
 					out.Attachments = (out.Attachments)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v5 int
-					v5 = int(in.Int())
+					var v5 Attachment
+					(v5).UnmarshalEasyJSON(in)
 					out.Attachments = append(out.Attachments, v5)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
+		case "geo":
+			if in.IsNull() {
+				in.Skip()
+				out.Geo = nil
+			} else {
+				if out.Geo == nil {
+					out.Geo = new(Geo)
+				}
+				easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain4(in, out.Geo)
+			}
 		case "is_hidden":
 			out.IsHidden = bool(in.Bool())
 		case "payload":
@@ -352,7 +403,7 @@ func easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain2(in *jlexer.Lexer, o
 		in.Consumed()
 	}
 }
-func easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain2(out *jwriter.Writer, in Message) {
+func easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain3(out *jwriter.Writer, in Message) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -402,11 +453,20 @@ func easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain2(out *jwriter.Writer
 				if v6 > 0 {
 					out.RawByte(',')
 				}
-				out.Int(int(v7))
+				(v7).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
 	}
+	{
+		const prefix string = ",\"reply_message\":"
+		out.RawString(prefix)
+		if in.ReplyMessage == nil {
+			out.RawString("null")
+		} else {
+			(*in.ReplyMessage).MarshalEasyJSON(out)
+		}
+	}
 	{
 		const prefix string = ",\"important\":"
 		out.RawString(prefix)
@@ -415,7 +475,7 @@ func easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain2(out *jwriter.Writer
 	{
 		const prefix string = ",\"random_id\":"
 		out.RawString(prefix)
-		out.Int32(int32(in.RandomId))
+		out.Int64(int64(in.RandomId))
 	}
 	{
 		const prefix string = ",\"attachments\":"
@@ -428,11 +488,20 @@ func easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain2(out *jwriter.Writer
 				if v8 > 0 {
 					out.RawByte(',')
 				}
-				out.Int(int(v9))
+				(v9).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
 	}
+	{
+		const prefix string = ",\"geo\":"
+		out.RawString(prefix)
+		if in.Geo == nil {
+			out.RawString("null")
+		} else {
+			easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain4(out, *in.Geo)
+		}
+	}
 	{
 		const prefix string = ",\"is_hidden\":"
 		out.RawString(prefix)
@@ -445,3 +514,221 @@ func easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain2(out *jwriter.Writer
 	}
 	out.RawByte('}')
 }
+
+// MarshalJSON supports json.Marshaler interface
+func (v Message) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Message) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Message) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Message) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain3(l, v)
+}
+func easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain4(in *jlexer.Lexer, out *Geo) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "type":
+			out.Type = string(in.String())
+		case "coordinates":
+			easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain5(in, &out.Coordinates)
+		case "place":
+			if in.IsNull() {
+				in.Skip()
+				out.Place = nil
+			} else {
+				if out.Place == nil {
+					out.Place = new(Place)
+				}
+				easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain6(in, out.Place)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain4(out *jwriter.Writer, in Geo) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"coordinates\":"
+		out.RawString(prefix)
+		easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain5(out, in.Coordinates)
+	}
+	{
+		const prefix string = ",\"place\":"
+		out.RawString(prefix)
+		if in.Place == nil {
+			out.RawString("null")
+		} else {
+			easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain6(out, *in.Place)
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain6(in *jlexer.Lexer, out *Place) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.Id = int(in.Int())
+		case "title":
+			out.Title = string(in.String())
+		case "latitude":
+			out.Latitude = float64(in.Float64())
+		case "longitude":
+			out.Longitude = float64(in.Float64())
+		case "country":
+			out.Country = string(in.String())
+		case "city":
+			out.City = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain6(out *jwriter.Writer, in Place) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Id))
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"latitude\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Latitude))
+	}
+	{
+		const prefix string = ",\"longitude\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Longitude))
+	}
+	{
+		const prefix string = ",\"country\":"
+		out.RawString(prefix)
+		out.String(string(in.Country))
+	}
+	{
+		const prefix string = ",\"city\":"
+		out.RawString(prefix)
+		out.String(string(in.City))
+	}
+	out.RawByte('}')
+}
+func easyjson3c9d2b01DecodeGithubComSepukaVkbotserverDomain5(in *jlexer.Lexer, out *Coordinates) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "latitude":
+			out.Latitude = float64(in.Float64())
+		case "longitude":
+			out.Longitude = float64(in.Float64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson3c9d2b01EncodeGithubComSepukaVkbotserverDomain5(out *jwriter.Writer, in Coordinates) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"latitude\":"
+		out.RawString(prefix[1:])
+		out.Float64(float64(in.Latitude))
+	}
+	{
+		const prefix string = ",\"longitude\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Longitude))
+	}
+	out.RawByte('}')
+}
@@ -0,0 +1,165 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// VK API error codes, see https://dev.vk.com/reference/errors
+const (
+	ApiCodeUnknown         = 1
+	ApiCodeAuthFailed      = 5
+	ApiCodeTooManyRequests = 6
+	ApiCodeFloodControl    = 9
+	ApiCodeInternal        = 10
+	ApiCodeAccessDenied    = 15
+	ApiCodeGroupAuthFailed = 27
+	ApiCodeAppAuthFailed   = 28
+	ApiCodeInvalidParam    = 100
+	ApiCodeBlacklisted     = 900
+	ApiCodeCantSendToUser  = 901
+)
+
+const (
+	// ClassNone is the class of nil error
+	ClassNone Class = iota
+	// ClassRetryable errors may disappear if the request is repeated later
+	ClassRetryable
+	// ClassPermanent errors repeat on every attempt
+	ClassPermanent
+	// ClassAuth errors mean the token is wrong or revoked
+	ClassAuth
+)
+
+var (
+	ApiAuthFailed      = errors.New(`VK API authorization failed`)
+	ApiTooManyRequests = errors.New(`VK API too many requests per second`)
+	ApiFloodControl    = errors.New(`VK API flood control`)
+	ApiInternal        = errors.New(`VK API internal server error`)
+	ApiAccessDenied    = errors.New(`VK API access denied`)
+	ApiInvalidParam    = errors.New(`VK API invalid parameter`)
+	ApiBlacklisted     = errors.New(`user is in the blacklist of the group`)
+	ApiCantSendToUser  = errors.New(`user has not allowed messages from the group`)
+
+	apiCodeErrors = map[int32]error{
+		ApiCodeAuthFailed:      ApiAuthFailed,
+		ApiCodeGroupAuthFailed: ApiAuthFailed,
+		ApiCodeAppAuthFailed:   ApiAuthFailed,
+		ApiCodeTooManyRequests: ApiTooManyRequests,
+		ApiCodeFloodControl:    ApiFloodControl,
+		ApiCodeInternal:        ApiInternal,
+		ApiCodeAccessDenied:    ApiAccessDenied,
+		ApiCodeInvalidParam:    ApiInvalidParam,
+		ApiCodeBlacklisted:     ApiBlacklisted,
+		ApiCodeCantSendToUser:  ApiCantSendToUser,
+	}
+)
+
+type (
+	// Class tells how to deal with an error
+	Class int
+
+	// ApiParam is the request param which VK returns with the error
+	ApiParam struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}
+
+	// ApiError is the error returned by VK API, errors.Is matches it with sentinels of known codes
+	// like errors.Is(err, ApiCantSendToUser)
+	ApiError struct {
+		Code    int32      `json:"error_code"`
+		Message string     `json:"error_msg"`
+		Params  []ApiParam `json:"request_params"`
+	}
+)
+
+// NewApiError instance an error returned by VK API
+func NewApiError(code int32, msg string, params ...ApiParam) *ApiError {
+	return &ApiError{
+		Code:    code,
+		Message: msg,
+		Params:  params,
+	}
+}
+
+func (e *ApiError) Error() string {
+	return fmt.Sprintf(`VK API error %d: %s`, e.Code, e.Message)
+}
+
+func (e *ApiError) Is(target error) bool {
+	if sentinel, ok := apiCodeErrors[e.Code]; ok && sentinel == target {
+		return true
+	}
+
+	if other, ok := target.(*ApiError); ok {
+		return other.Code == e.Code
+	}
+
+	return false
+}
+
+// Param returns the value of the request param
+func (e *ApiError) Param(key string) string {
+	for _, param := range e.Params {
+		if param.Key == key {
+			return param.Value
+		}
+	}
+
+	return ``
+}
+
+// Classify tells whether the error is retryable, permanent or an auth problem.
+// VK internal errors and 5xx responses, rate limits (including the client-side one), flood control
+// and network errors are retryable, canceled requests are permanent
+func Classify(err error) Class {
+	var (
+		apiErr *ApiError
+		netErr net.Error
+	)
+
+	switch {
+	case err == nil:
+		return ClassNone
+	case errors.As(err, &apiErr):
+		switch apiErr.Code {
+		case ApiCodeAuthFailed, ApiCodeGroupAuthFailed, ApiCodeAppAuthFailed:
+			return ClassAuth
+		case ApiCodeUnknown, ApiCodeTooManyRequests, ApiCodeFloodControl, ApiCodeInternal:
+			return ClassRetryable
+		default:
+			return ClassPermanent
+		}
+	case errors.Is(err, RateLimited), errors.Is(err, ApiUnavailable):
+		return ClassRetryable
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return ClassPermanent
+	case errors.As(err, &netErr):
+		return ClassRetryable
+	default:
+		return ClassPermanent
+	}
+}
+
+// IsRetryable is a shortcut for Classify(err) == ClassRetryable
+func IsRetryable(err error) bool {
+	return Classify(err) == ClassRetryable
+}
+
+// IsPermanent is a shortcut for Classify(err) == ClassPermanent
+func IsPermanent(err error) bool {
+	return Classify(err) == ClassPermanent
+}
+
+// IsAuth is a shortcut for Classify(err) == ClassAuth
+func IsAuth(err error) bool {
+	return Classify(err) == ClassAuth
+}
+
+// IsRateLimited tells whether the call was rejected by the client-side rate limiter, unlike VK codes 6 and 9
+func IsRateLimited(err error) bool {
+	return errors.Is(err, RateLimited)
+}
@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return `i/o timeout` }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestApiError_Is(t *testing.T) {
+	var err error = fmt.Errorf(`send message: %w`, NewApiError(ApiCodeCantSendToUser, `Can't send messages for users without permission`))
+
+	assert.ErrorIs(t, err, ApiCantSendToUser)
+	assert.ErrorIs(t, err, &ApiError{Code: ApiCodeCantSendToUser})
+	assert.NotErrorIs(t, err, ApiBlacklisted)
+	assert.ErrorIs(t, NewApiError(ApiCodeGroupAuthFailed, `Group authorization failed`), ApiAuthFailed)
+}
+
+func TestApiError_Param(t *testing.T) {
+	var err = NewApiError(ApiCodeInvalidParam, `One of the parameters specified was missing or invalid`, ApiParam{Key: `method`, Value: `messages.send`})
+
+	assert.Equal(t, `messages.send`, err.Param(`method`))
+	assert.Empty(t, err.Param(`peer_id`))
+}
+
+func TestClassify(t *testing.T) {
+	var tests = map[string]struct {
+		err      error
+		expected Class
+	}{
+		`nil`:               {err: nil, expected: ClassNone},
+		`auth failed`:       {err: NewApiError(ApiCodeAuthFailed, ``), expected: ClassAuth},
+		`too many requests`: {err: NewApiError(ApiCodeTooManyRequests, ``), expected: ClassRetryable},
+		`internal`:          {err: NewApiError(ApiCodeInternal, ``), expected: ClassRetryable},
+		`can't send`:        {err: NewApiError(ApiCodeCantSendToUser, ``), expected: ClassPermanent},
+		`flood control`:     {err: NewApiError(ApiCodeFloodControl, ``), expected: ClassRetryable},
+		`access denied`:     {err: NewApiError(ApiCodeAccessDenied, ``), expected: ClassPermanent},
+		`5xx response`:      {err: NewApiUnavailableError(`messages.send answered 502`), expected: ClassRetryable},
+		`network timeout`:   {err: &url.Error{Op: `Post`, URL: `https://api.vk.com`, Err: timeoutError{}}, expected: ClassRetryable},
+		`canceled request`:  {err: &url.Error{Op: `Post`, URL: `https://api.vk.com`, Err: context.Canceled}, expected: ClassPermanent},
+		`other error`:       {err: errors.New(`invalid JSON`), expected: ClassPermanent},
+	}
+
+	for testName, testCase := range tests {
+		assert.Equal(t, testCase.expected, Classify(testCase.err), testName)
+	}
+
+	assert.True(t, IsRetryable(NewApiError(ApiCodeTooManyRequests, ``)))
+	assert.True(t, IsPermanent(NewApiError(ApiCodeAccessDenied, ``)))
+	assert.True(t, IsAuth(NewApiError(ApiCodeAppAuthFailed, ``)))
+}
@@ -7,6 +7,18 @@ var (
 	NotIsOAuthRequest = errors.New(`not is an OAuth request`)
 	OauthError        = errors.New(`oauth error`)
 	NoUserFound       = errors.New(`there are any user was found`)
+	InvalidSecret     = errors.New(`invalid secret key`)
+	QueueOverflow     = errors.New(`queue is full`)
+	PoolClosed        = errors.New(`worker pool is closed`)
+	WrongEventType    = errors.New(`wrong event type`)
+	UnknownState      = errors.New(`unknown dialog state`)
+	InvalidKeyboard   = errors.New(`invalid keyboard`)
+	InvalidTemplate   = errors.New(`invalid message template`)
+	RateLimited       = errors.New(`API rate limit exceeded`)
+	ApiUnavailable    = errors.New(`VK API is unavailable`)
+	UploadFailed      = errors.New(`file upload failed`)
+	BroadcastFinished = errors.New(`broadcast is finished`)
+	InvalidSchedule   = errors.New(`invalid schedule`)
 )
 
 // NewInvalidJsonError instance an InvalidJson error
@@ -36,3 +48,99 @@ func NewNoUserFound() BotError {
 		err: NoUserFound,
 	}
 }
+
+// NewInvalidSecretError instance an error about an event with a forged secret key
+func NewInvalidSecretError(msg string) BotError {
+	return BotError{
+		err:     InvalidSecret,
+		message: msg,
+	}
+}
+
+// NewQueueOverflowError instance an error about an event which could not be queued
+func NewQueueOverflowError() BotError {
+	return BotError{
+		err:     QueueOverflow,
+		message: QueueOverflow.Error(),
+	}
+}
+
+// NewPoolClosedError instance an error about an event which came after the pool had been closed
+func NewPoolClosedError() BotError {
+	return BotError{
+		err:     PoolClosed,
+		message: PoolClosed.Error(),
+	}
+}
+
+// NewWrongEventTypeError instance an error about an event object requested for the event of other type
+func NewWrongEventTypeError(msg string) BotError {
+	return BotError{
+		err:     WrongEventType,
+		message: msg,
+	}
+}
+
+// NewUnknownStateError instance an error about a transition to the undefined dialog state
+func NewUnknownStateError(msg string) BotError {
+	return BotError{
+		err:     UnknownState,
+		message: msg,
+	}
+}
+
+// NewInvalidKeyboardError instance an error about a keyboard which VK would reject
+func NewInvalidKeyboardError(msg string) BotError {
+	return BotError{
+		err:     InvalidKeyboard,
+		message: msg,
+	}
+}
+
+// NewInvalidTemplateError instance an error about a message template which VK would reject
+func NewInvalidTemplateError(msg string) BotError {
+	return BotError{
+		err:     InvalidTemplate,
+		message: msg,
+	}
+}
+
+// NewRateLimitedError instance an error about an API call rejected by the client-side rate limiter
+func NewRateLimitedError(msg string) BotError {
+	return BotError{
+		err:     RateLimited,
+		message: msg,
+	}
+}
+
+// NewApiUnavailableError instance an error about a 5xx response of VK API
+func NewApiUnavailableError(msg string) BotError {
+	return BotError{
+		err:     ApiUnavailable,
+		message: msg,
+	}
+}
+
+// NewUploadFailedError instance an error about a file which VK has not accepted
+func NewUploadFailedError(msg string) BotError {
+	return BotError{
+		err:     UploadFailed,
+		message: msg,
+	}
+}
+
+// NewBroadcastFinishedError instance an error about a control of the broadcast which is done or cancelled
+func NewBroadcastFinishedError(msg string) BotError {
+	return BotError{
+		err:     BroadcastFinished,
+		message: msg,
+	}
+}
+
+// NewInvalidScheduleError instance an error about a cron expression which can't be parsed
+func NewInvalidScheduleError(msg string) BotError {
+	return BotError{
+		err:     InvalidSchedule,
+		message: msg,
+	}
+}
@@ -1,6 +1,7 @@
 package message
 
 import (
+	"context"
 	"fmt"
 	"github.com/mailru/easyjson"
 	"github.com/sepuka/vkbotserver/api"
@@ -12,6 +13,7 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -27,6 +29,7 @@ type authVk struct {
 	userRepo  domain.UserRepository
 	sessions  domain.SessionsRepository
 	callbacks []domain.Callback
+	running   sync.WaitGroup
 }
 
 // NewAuthVk creates an instance VK VkOauth handler
@@ -49,6 +52,10 @@ func NewAuthVk(
 }
 
 func (o *authVk) Exec(req *domain.Request, resp http.ResponseWriter) error {
+	return o.ExecContext(context.Background(), req, resp)
+}
+
+func (o *authVk) ExecContext(ctx context.Context, req *domain.Request, resp http.ResponseWriter) error {
 	const (
 		urlPartCode = `code`
 	)
@@ -84,7 +91,7 @@ func (o *authVk) Exec(req *domain.Request, resp http.ResponseWriter) error {
 
 	tokenUrl = fmt.Sprintf(tokenUrlTemplate, o.cfg.ClientId, o.cfg.ClientSecret, o.cfg.RedirectUri, args[urlPartCode][0])
 
-	if tokenHttpRequest, err = http.NewRequest(`GET`, tokenUrl, nil); err != nil {
+	if tokenHttpRequest, err = http.NewRequestWithContext(ctx, `GET`, tokenUrl, nil); err != nil {
 		o.
 			logger.
 			With(
@@ -200,7 +207,11 @@ func (o *authVk) Exec(req *domain.Request, resp http.ResponseWriter) error {
 	user.Token = tokenResponse.Token
 
 	for _, callback = range o.callbacks {
-		go callback(user)
+		o.running.Add(1)
+		go func(callback domain.Callback) {
+			defer o.running.Done()
+			callback(user)
+		}(callback)
 	}
 
 	if siteUrl, err = url.Parse(o.cfg.RedirectUri); err != nil {
@@ -223,6 +234,11 @@ func (o *authVk) Exec(req *domain.Request, resp http.ResponseWriter) error {
 	return nil
 }
 
+// Wait waits for running callbacks
+func (o *authVk) Wait() {
+	o.running.Wait()
+}
+
 func (o *authVk) String() string {
 	return domain.OauthVkHandlerName
 }
@@ -25,5 +25,5 @@ func (o *confirmation) Exec(req *domain.Request, resp http.ResponseWriter) error
 }
 
 func (o *confirmation) String() string {
-	return `confirmation`
+	return domain.EventConfirmation
 }
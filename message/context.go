@@ -0,0 +1,71 @@
+package message
+
+import (
+	"context"
+	"github.com/sepuka/vkbotserver/api/button"
+	"github.com/sepuka/vkbotserver/domain"
+	"net/http"
+)
+
+type (
+	// ContextExecutor is the context-aware variant of the Executor
+	ContextExecutor interface {
+		ExecContext(context.Context, *domain.Request, http.ResponseWriter) error
+	}
+
+	// ContextHandler is the context-aware variant of the Handler
+	ContextHandler interface {
+		HandleContext(context.Context, *domain.Request, *button.Payload) error
+	}
+
+	// HandlerFunc allows to use ordinary functions as both Handler and ContextHandler
+	HandlerFunc func(context.Context, *domain.Request, *button.Payload) error
+
+	// boundExecutor keeps ctx for executors which are called through context-unaware code
+	boundExecutor struct {
+		ctx  context.Context
+		exec Executor
+	}
+)
+
+// ExecContext runs the executor passing ctx to it when the executor is context-aware
+func ExecContext(ctx context.Context, exec Executor, req *domain.Request, resp http.ResponseWriter) error {
+	if executor, ok := exec.(ContextExecutor); ok {
+		return executor.ExecContext(ctx, req, resp)
+	}
+
+	return exec.Exec(req, resp)
+}
+
+// HandleContext runs the handler passing ctx to it when the handler is context-aware
+func HandleContext(ctx context.Context, handler Handler, req *domain.Request, payload *button.Payload) error {
+	if h, ok := handler.(ContextHandler); ok {
+		return h.HandleContext(ctx, req, payload)
+	}
+
+	return handler.Handle(req, payload)
+}
+
+// WithContext binds ctx to the executor, its Exec passes ctx to the wrapped context-aware executor
+func WithContext(ctx context.Context, exec Executor) Executor {
+	return &boundExecutor{
+		ctx:  ctx,
+		exec: exec,
+	}
+}
+
+func (e *boundExecutor) Exec(req *domain.Request, resp http.ResponseWriter) error {
+	return ExecContext(e.ctx, e.exec, req, resp)
+}
+
+func (e *boundExecutor) ExecContext(ctx context.Context, req *domain.Request, resp http.ResponseWriter) error {
+	return ExecContext(ctx, e.exec, req, resp)
+}
+
+func (f HandlerFunc) Handle(req *domain.Request, payload *button.Payload) error {
+	return f(context.Background(), req, payload)
+}
+
+func (f HandlerFunc) HandleContext(ctx context.Context, req *domain.Request, payload *button.Payload) error {
+	return f(ctx, req, payload)
+}
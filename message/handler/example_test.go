@@ -0,0 +1,26 @@
+package handler_test
+
+import (
+	"github.com/sepuka/vkbotserver/api"
+	"github.com/sepuka/vkbotserver/config"
+	"github.com/sepuka/vkbotserver/message"
+	"github.com/sepuka/vkbotserver/message/handler"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+func ExampleNewStartHandler() {
+	var (
+		cfg    = config.Config{}
+		logger = zap.NewNop().Sugar()
+		vk     = api.NewApi(logger, cfg, &http.Client{}, api.NewRnder())
+		start  = handler.NewStartHandler(vk)
+		router = message.NewRouter().
+			Command(`start`, start).
+			Payload(`start`, start)
+	)
+
+	_ = message.HandlerMap{
+		router.String(): router,
+	}
+}
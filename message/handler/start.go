@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"github.com/sepuka/vkbotserver/api"
 	"github.com/sepuka/vkbotserver/api/button"
 	"github.com/sepuka/vkbotserver/domain"
@@ -21,9 +22,14 @@ func NewStartHandler(api *api.Api) *startHandler {
 }
 
 func (h *startHandler) Handle(req *domain.Request, payload *button.Payload) error {
+	return h.HandleContext(context.Background(), req, payload)
+}
+
+func (h *startHandler) HandleContext(ctx context.Context, req *domain.Request, payload *button.Payload) error {
 	var (
 		peerId = int(req.Object.Message.FromId)
+		_, err = h.api.SendMessageContext(ctx, peerId, msg)
 	)
 
-	return h.api.SendMessage(peerId, msg)
+	return err
 }
@@ -12,3 +12,9 @@ type HandlerMap map[string]Executor
 type Executor interface {
 	Exec(*domain.Request, http.ResponseWriter) error
 }
+
+// Waiter is implemented by executors which run some work in background
+// the server waits for it on shutdown
+type Waiter interface {
+	Wait()
+}
@@ -0,0 +1,222 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/sepuka/vkbotserver/api"
+	"github.com/sepuka/vkbotserver/api/button"
+	"github.com/sepuka/vkbotserver/domain"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// the bot mention like "[club123|@bot] " which VK adds to messages in chats
+var mentionRegexp = regexp.MustCompile(`^\[(club|public)\d+\|[^\]]*\][,\s]*`)
+
+type (
+	// Middleware wraps handlers of the router
+	Middleware func(ContextHandler) ContextHandler
+
+	argsKey struct{}
+
+	route struct {
+		match   func(text string, payload *button.Payload) ([]string, bool)
+		handler ContextHandler
+	}
+
+	routes struct {
+		list      []route
+		callbacks map[string]ContextHandler
+		fallback  ContextHandler
+	}
+
+	// Router is the message_new executor which dispatches messages by text or button payload.
+	// Routes are checked in order of registration, the first matched one handles the message.
+	// The router handles message_event of callback buttons too when it's registered for this event
+	Router struct {
+		routes     *routes
+		middleware []Middleware
+	}
+)
+
+// NewRouter creates router, the middleware wraps each handler of the router
+func NewRouter(middleware ...Middleware) *Router {
+	return &Router{
+		routes:     &routes{callbacks: map[string]ContextHandler{}},
+		middleware: middleware,
+	}
+}
+
+// Args returns arguments of the prefix route or submatches of the regexp route
+func Args(ctx context.Context) []string {
+	var args, _ = ctx.Value(argsKey{}).([]string)
+
+	return args
+}
+
+// Group creates routes group which handlers are wrapped by router and group middleware both
+func (r *Router) Group(middleware ...Middleware) *Router {
+	var chain = make([]Middleware, 0, len(r.middleware)+len(middleware))
+
+	chain = append(chain, r.middleware...)
+	chain = append(chain, middleware...)
+
+	return &Router{
+		routes:     r.routes,
+		middleware: chain,
+	}
+}
+
+// Command routes messages which text equals the command ignoring case and the bot mention
+func (r *Router) Command(command string, handler Handler) *Router {
+	return r.add(func(text string, payload *button.Payload) ([]string, bool) {
+		return nil, strings.EqualFold(text, command)
+	}, handler)
+}
+
+// Prefix routes messages starting with the prefix, the rest of the text split by spaces is available by Args
+func (r *Router) Prefix(prefix string, handler Handler) *Router {
+	return r.add(func(text string, payload *button.Payload) ([]string, bool) {
+		if len(text) < len(prefix) || !strings.EqualFold(text[:len(prefix)], prefix) {
+			return nil, false
+		}
+
+		var rest = text[len(prefix):]
+		if rest != `` && !strings.HasPrefix(rest, ` `) {
+			return nil, false
+		}
+
+		return strings.Fields(rest), true
+	}, handler)
+}
+
+// Regexp routes messages matching the expression, submatches are available by Args
+func (r *Router) Regexp(expr *regexp.Regexp, handler Handler) *Router {
+	return r.add(func(text string, payload *button.Payload) ([]string, bool) {
+		var matches = expr.FindStringSubmatch(text)
+		if matches == nil {
+			return nil, false
+		}
+
+		return matches[1:], true
+	}, handler)
+}
+
+// Payload routes messages sent by keyboard buttons with the command in the payload
+func (r *Router) Payload(command string, handler Handler) *Router {
+	return r.add(func(text string, payload *button.Payload) ([]string, bool) {
+		return nil, payload != nil && payload.Command == command
+	}, handler)
+}
+
+// Callback routes message_event of callback buttons with the command in the payload,
+// the handler gets the event by req.MessageEvent() and should answer it by api.SendMessageEventAnswer
+func (r *Router) Callback(command string, handler Handler) *Router {
+	r.routes.callbacks[command] = r.wrap(handler)
+
+	return r
+}
+
+// Fallback handles messages which match no route
+func (r *Router) Fallback(handler Handler) *Router {
+	r.routes.fallback = r.wrap(handler)
+
+	return r
+}
+
+func (r *Router) Exec(req *domain.Request, resp http.ResponseWriter) error {
+	return r.ExecContext(context.Background(), req, resp)
+}
+
+func (r *Router) ExecContext(ctx context.Context, req *domain.Request, resp http.ResponseWriter) error {
+	var (
+		text    = mentionRegexp.ReplaceAllString(strings.TrimSpace(req.Object.Message.Text), ``)
+		payload = r.payload(req)
+		handler = r.routes.fallback
+		args    []string
+		err     error
+	)
+
+	if req.Type == domain.EventMessageEvent {
+		return r.callback(ctx, req, resp)
+	}
+
+	for _, rt := range r.routes.list {
+		if matchedArgs, ok := rt.match(text, payload); ok {
+			handler, args = rt.handler, matchedArgs
+			break
+		}
+	}
+
+	if handler != nil {
+		if err = handler.HandleContext(context.WithValue(ctx, argsKey{}, args), req, payload); err != nil {
+			return err
+		}
+	}
+
+	_, err = resp.Write(api.DefaultResponseBody())
+
+	return err
+}
+
+func (r *Router) callback(ctx context.Context, req *domain.Request, resp http.ResponseWriter) error {
+	var (
+		payload = &button.Payload{}
+		event   *domain.MessageEvent
+		err     error
+	)
+
+	if event, err = req.MessageEvent(); err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(event.Payload, payload); err == nil {
+		if handler, ok := r.routes.callbacks[payload.Command]; ok {
+			if err = handler.HandleContext(ctx, req, payload); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = resp.Write(api.DefaultResponseBody())
+
+	return err
+}
+
+func (r *Router) String() string {
+	return domain.EventMessageNew
+}
+
+func (r *Router) add(match func(string, *button.Payload) ([]string, bool), handler Handler) *Router {
+	r.routes.list = append(r.routes.list, route{match: match, handler: r.wrap(handler)})
+
+	return r
+}
+
+func (r *Router) wrap(handler Handler) ContextHandler {
+	var wrapped ContextHandler = HandlerFunc(func(ctx context.Context, req *domain.Request, payload *button.Payload) error {
+		return HandleContext(ctx, handler, req, payload)
+	})
+
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		wrapped = r.middleware[i](wrapped)
+	}
+
+	return wrapped
+}
+
+// payload returns the keyboard button payload or nil if the message is typed by a user
+func (r *Router) payload(req *domain.Request) *button.Payload {
+	var payload = &button.Payload{}
+
+	if !req.IsKeyboardButton() {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(req.Object.Message.Payload), payload); err != nil {
+		return nil
+	}
+
+	return payload
+}
@@ -0,0 +1,170 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"github.com/mailru/easyjson"
+	"github.com/sepuka/vkbotserver/api/button"
+	"github.com/sepuka/vkbotserver/domain"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+type routeRecorder struct {
+	name string
+	args []string
+}
+
+func (r *routeRecorder) handler(name string) HandlerFunc {
+	return func(ctx context.Context, req *domain.Request, payload *button.Payload) error {
+		r.name = name
+		r.args = Args(ctx)
+
+		return nil
+	}
+}
+
+func TestRouter_Exec(t *testing.T) {
+	var (
+		recorder = &routeRecorder{}
+		trace    []string
+		logging  = func(next ContextHandler) ContextHandler {
+			return HandlerFunc(func(ctx context.Context, req *domain.Request, payload *button.Payload) error {
+				trace = append(trace, `admin`)
+				return next.HandleContext(ctx, req, payload)
+			})
+		}
+		router = NewRouter()
+		admin  = router.Group(logging)
+
+		tests = map[string]struct {
+			text     string
+			payload  string
+			expected string
+			args     []string
+			trace    []string
+		}{
+			`exact command`: {
+				text:     `Start`,
+				expected: `start`,
+			},
+			`command with mention`: {
+				text:     `[club123|@bot] start`,
+				expected: `start`,
+			},
+			`prefix with args`: {
+				text:     `/weather Moscow tomorrow`,
+				expected: `weather`,
+				args:     []string{`Moscow`, `tomorrow`},
+			},
+			`prefix must be a whole word`: {
+				text:     `/weatherman`,
+				expected: `fallback`,
+			},
+			`regexp`: {
+				text:     `remind me in 15 minutes`,
+				expected: `remind`,
+				args:     []string{`15`},
+			},
+			`button payload`: {
+				text:     `Some button label`,
+				payload:  `{"command":"menu"}`,
+				expected: `menu`,
+			},
+			`group route`: {
+				text:     `ban`,
+				expected: `ban`,
+				trace:    []string{`admin`},
+			},
+			`fallback`: {
+				text:     `what?`,
+				expected: `fallback`,
+			},
+		}
+	)
+
+	router.
+		Command(`start`, recorder.handler(`start`)).
+		Prefix(`/weather`, recorder.handler(`weather`)).
+		Regexp(regexp.MustCompile(`^remind me in (\d+) minutes$`), recorder.handler(`remind`)).
+		Payload(`menu`, recorder.handler(`menu`)).
+		Fallback(recorder.handler(`fallback`))
+	admin.Command(`ban`, recorder.handler(`ban`))
+
+	for testName, testCase := range tests {
+		var (
+			resp = httptest.NewRecorder()
+			req  = &domain.Request{
+				Type: domain.EventMessageNew,
+				Object: domain.Object{
+					Message: domain.Message{Text: testCase.text, Payload: testCase.payload},
+				},
+			}
+		)
+
+		recorder.name, recorder.args, trace = ``, nil, nil
+
+		assert.Nil(t, router.Exec(req, resp), testName)
+		assert.Equal(t, testCase.expected, recorder.name, testName)
+		assert.Equal(t, testCase.args, recorder.args, testName)
+		assert.Equal(t, testCase.trace, trace, testName)
+		assert.Equal(t, `ok`, resp.Body.String(), testName)
+	}
+}
+
+func TestRouter_ExecError(t *testing.T) {
+	var (
+		expected = errors.New(`handler error`)
+		router   = NewRouter().Command(`start`, HandlerFunc(func(ctx context.Context, req *domain.Request, payload *button.Payload) error {
+			return expected
+		}))
+		req = &domain.Request{
+			Object: domain.Object{Message: domain.Message{Text: `start`}},
+		}
+		resp = httptest.NewRecorder()
+	)
+
+	assert.ErrorIs(t, router.Exec(req, resp), expected)
+	assert.Empty(t, resp.Body.String())
+}
+
+func TestRouter_ExecCallback(t *testing.T) {
+	var (
+		recorder = &routeRecorder{}
+		router   = NewRouter().
+				Payload(`like`, recorder.handler(`payload`)).
+				Callback(`like`, recorder.handler(`callback`))
+		tests = map[string]struct {
+			payload  string
+			expected string
+		}{
+			`matched command`: {
+				payload:  `{"group_id":1,"type":"message_event","event_id":"a1","object":{"user_id":557404793,"peer_id":557404793,"event_id":"e6ba7d6a3c3f","payload":{"command":"like"},"conversation_message_id":568}}`,
+				expected: `callback`,
+			},
+			`unknown command`: {
+				payload: `{"group_id":1,"type":"message_event","event_id":"a2","object":{"user_id":557404793,"peer_id":557404793,"event_id":"e6ba7d6a3c3f","payload":{"command":"dislike"},"conversation_message_id":568}}`,
+			},
+			`invalid payload`: {
+				payload: `{"group_id":1,"type":"message_event","event_id":"a3","object":{"user_id":557404793,"peer_id":557404793,"event_id":"e6ba7d6a3c3f","payload":"like","conversation_message_id":568}}`,
+			},
+		}
+	)
+
+	for testName, testCase := range tests {
+		var (
+			resp = httptest.NewRecorder()
+			req  = &domain.Request{}
+		)
+
+		assert.Nil(t, easyjson.Unmarshal([]byte(testCase.payload), req), testName)
+
+		recorder.name = ``
+
+		assert.Nil(t, router.Exec(req, resp), testName)
+		assert.Equal(t, testCase.expected, recorder.name, testName)
+		assert.Equal(t, `ok`, resp.Body.String(), testName)
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"github.com/sepuka/vkbotserver/api"
+	"github.com/sepuka/vkbotserver/domain"
+	"github.com/sepuka/vkbotserver/message"
+	"net/http"
+	"sync/atomic"
+)
+
+type (
+	// EventStore remembers ids of the handled events
+	EventStore interface {
+		// Remember marks the event as seen and tells whether it was seen before
+		Remember(eventId string) (bool, error)
+		// Forget removes the event so that VK retry will be handled again
+		Forget(eventId string) error
+	}
+
+	// Dedup suppresses events which VK retries because it had not got "ok" in time
+	Dedup struct {
+		store      EventStore
+		suppressed uint64
+	}
+)
+
+// NewDedup creates deduplication middleware on top of the store
+func NewDedup(store EventStore) *Dedup {
+	return &Dedup{
+		store: store,
+	}
+}
+
+// Middleware answers "ok" to already seen events without calling next handlers
+func (d *Dedup) Middleware(next HandlerFunc) HandlerFunc {
+	return func(exec message.Executor, req *domain.Request, w http.ResponseWriter) error {
+		var (
+			seen bool
+			err  error
+		)
+
+		if req.EventId == `` {
+			return next(exec, req, w)
+		}
+
+		if seen, err = d.store.Remember(req.EventId); err != nil {
+			return next(exec, req, w)
+		}
+
+		if seen {
+			atomic.AddUint64(&d.suppressed, 1)
+			_, err = w.Write(api.DefaultResponseBody())
+
+			return err
+		}
+
+		if err = next(exec, req, w); err != nil {
+			_ = d.store.Forget(req.EventId)
+		}
+
+		return err
+	}
+}
+
+// Suppressed returns the number of duplicates which were not handled
+func (d *Dedup) Suppressed() uint64 {
+	return atomic.LoadUint64(&d.suppressed)
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"sync"
+	"time"
+)
+
+const (
+	dedupKeyTmpl = `vkbot_server_middleware_dedup_%s`
+)
+
+type (
+	memoryEventStore struct {
+		mu     sync.Mutex
+		size   int
+		ttl    time.Duration
+		now    func() time.Time
+		items  map[string]*list.Element
+		events *list.List
+	}
+
+	memoryEvent struct {
+		id      string
+		expires time.Time
+	}
+
+	redisEventStore struct {
+		client *redis.Client
+		ttl    time.Duration
+	}
+)
+
+// NewMemoryEventStore creates LRU store keeping up to size events during ttl
+func NewMemoryEventStore(size int, ttl time.Duration) *memoryEventStore {
+	return &memoryEventStore{
+		size:   size,
+		ttl:    ttl,
+		now:    time.Now,
+		items:  make(map[string]*list.Element, size),
+		events: list.New(),
+	}
+}
+
+func (s *memoryEventStore) Remember(eventId string) (bool, error) {
+	var (
+		now     = s.now()
+		element *list.Element
+		event   *memoryEvent
+		ok      bool
+	)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok = s.items[eventId]; ok {
+		event = element.Value.(*memoryEvent)
+		s.events.MoveToFront(element)
+		if now.Before(event.expires) {
+			return true, nil
+		}
+
+		event.expires = now.Add(s.ttl)
+
+		return false, nil
+	}
+
+	s.items[eventId] = s.events.PushFront(&memoryEvent{id: eventId, expires: now.Add(s.ttl)})
+
+	for s.size > 0 && s.events.Len() > s.size {
+		element = s.events.Back()
+		s.events.Remove(element)
+		delete(s.items, element.Value.(*memoryEvent).id)
+	}
+
+	return false, nil
+}
+
+func (s *memoryEventStore) Forget(eventId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.items[eventId]; ok {
+		s.events.Remove(element)
+		delete(s.items, eventId)
+	}
+
+	return nil
+}
+
+// NewRedisEventStore creates store which keeps events in redis during ttl
+func NewRedisEventStore(client *redis.Client, ttl time.Duration) *redisEventStore {
+	return &redisEventStore{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func (s *redisEventStore) Remember(eventId string) (bool, error) {
+	var isNew, err = s.client.SetNX(context.Background(), fmt.Sprintf(dedupKeyTmpl, eventId), 1, s.ttl).Result()
+
+	return !isNew, err
+}
+
+func (s *redisEventStore) Forget(eventId string) error {
+	return s.client.Del(context.Background(), fmt.Sprintf(dedupKeyTmpl, eventId)).Err()
+}
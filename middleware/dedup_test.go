@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/sepuka/vkbotserver/domain"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type countingExecutor struct {
+	calls int
+	err   error
+}
+
+func (e *countingExecutor) Exec(req *domain.Request, resp http.ResponseWriter) error {
+	e.calls++
+
+	return e.err
+}
+
+func TestDedup_Middleware(t *testing.T) {
+	var (
+		store    = NewMemoryEventStore(10, time.Minute)
+		dedup    = NewDedup(store)
+		chain    = BuildHandlerChain([]func(HandlerFunc) HandlerFunc{dedup.Middleware})
+		executor = &countingExecutor{}
+		first    = &domain.Request{Type: `message_new`, EventId: `first`}
+		second   = &domain.Request{Type: `message_new`, EventId: `second`}
+		noId     = &domain.Request{Type: `confirmation`}
+		resp     *httptest.ResponseRecorder
+	)
+
+	for _, req := range []*domain.Request{first, first, second, noId, noId, first} {
+		resp = httptest.NewRecorder()
+		assert.Nil(t, chain(executor, req, resp))
+	}
+
+	assert.Equal(t, 4, executor.calls)
+	assert.Equal(t, uint64(2), dedup.Suppressed())
+	assert.Equal(t, `ok`, resp.Body.String())
+}
+
+func TestDedup_MiddlewareForgetsFailedEvents(t *testing.T) {
+	var (
+		dedup    = NewDedup(NewMemoryEventStore(10, time.Minute))
+		executor = &countingExecutor{err: errors.New(`temporary error`)}
+		req      = &domain.Request{Type: `message_new`, EventId: `event`}
+		chain    = dedup.Middleware(final)
+	)
+
+	assert.NotNil(t, chain(executor, req, httptest.NewRecorder()))
+	executor.err = nil
+	assert.Nil(t, chain(executor, req, httptest.NewRecorder()))
+	assert.Nil(t, chain(executor, req, httptest.NewRecorder()))
+
+	assert.Equal(t, 2, executor.calls)
+	assert.Equal(t, uint64(1), dedup.Suppressed())
+}
+
+func TestMemoryEventStore_Remember(t *testing.T) {
+	var (
+		now   = time.Now()
+		store = NewMemoryEventStore(2, time.Minute)
+		seen  bool
+	)
+
+	store.now = func() time.Time {
+		return now
+	}
+
+	seen, _ = store.Remember(`a`)
+	assert.False(t, seen)
+	seen, _ = store.Remember(`a`)
+	assert.True(t, seen)
+
+	// the oldest event is evicted when the size is exceeded
+	_, _ = store.Remember(`b`)
+	_, _ = store.Remember(`c`)
+	seen, _ = store.Remember(`a`)
+	assert.False(t, seen)
+
+	// the event is forgotten when ttl is expired
+	now = now.Add(time.Minute)
+	seen, _ = store.Remember(`c`)
+	assert.False(t, seen)
+	seen, _ = store.Remember(`c`)
+	assert.True(t, seen)
+}
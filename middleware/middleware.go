@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"github.com/sepuka/vkbotserver/domain"
 	"github.com/sepuka/vkbotserver/message"
 	"net/http"
@@ -19,3 +20,39 @@ func BuildHandlerChain(handlers []func(HandlerFunc) HandlerFunc) HandlerFunc {
 
 	return handlers[0](BuildHandlerChain(handlers[1:]))
 }
+
+// ContextHandlerFunc is the context-aware variant of the HandlerFunc
+type ContextHandlerFunc func(context.Context, message.Executor, *domain.Request, http.ResponseWriter) error
+
+func finalContext(ctx context.Context, handler message.Executor, req *domain.Request, resp http.ResponseWriter) error {
+	return message.ExecContext(ctx, handler, req, resp)
+}
+
+// BuildContextHandlerChain is the context-aware variant of the BuildHandlerChain
+func BuildContextHandlerChain(handlers []func(ContextHandlerFunc) ContextHandlerFunc) ContextHandlerFunc {
+	if len(handlers) == 0 {
+		return finalContext
+	}
+
+	return handlers[0](BuildContextHandlerChain(handlers[1:]))
+}
+
+// WithContext adapts the HandlerFunc chain, ctx reaches context-aware executors through the chain
+func WithContext(next HandlerFunc) ContextHandlerFunc {
+	return func(ctx context.Context, exec message.Executor, req *domain.Request, resp http.ResponseWriter) error {
+		return next(message.WithContext(ctx, exec), req, resp)
+	}
+}
+
+// ContextMiddleware adapts the middleware like Panic or Cache to the context-aware chain
+func ContextMiddleware(middleware func(HandlerFunc) HandlerFunc) func(ContextHandlerFunc) ContextHandlerFunc {
+	return func(next ContextHandlerFunc) ContextHandlerFunc {
+		return func(ctx context.Context, exec message.Executor, req *domain.Request, resp http.ResponseWriter) error {
+			var handler = middleware(func(exec message.Executor, req *domain.Request, resp http.ResponseWriter) error {
+				return next(ctx, exec, req, resp)
+			})
+
+			return handler(exec, req, resp)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"github.com/sepuka/vkbotserver/domain"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type ctxKey struct{}
+
+type contextExecutor struct {
+	value interface{}
+}
+
+func (e *contextExecutor) Exec(req *domain.Request, resp http.ResponseWriter) error {
+	return e.ExecContext(context.Background(), req, resp)
+}
+
+func (e *contextExecutor) ExecContext(ctx context.Context, req *domain.Request, resp http.ResponseWriter) error {
+	e.value = ctx.Value(ctxKey{})
+
+	return nil
+}
+
+func TestContextPropagation(t *testing.T) {
+	var (
+		ctx   = context.WithValue(context.Background(), ctxKey{}, `trace-id`)
+		req   = &domain.Request{}
+		tests = map[string]ContextHandlerFunc{
+			`legacy chain`: WithContext(BuildHandlerChain([]func(HandlerFunc) HandlerFunc{Panic})),
+			`context chain`: BuildContextHandlerChain([]func(ContextHandlerFunc) ContextHandlerFunc{
+				ContextMiddleware(Panic),
+			}),
+		}
+	)
+
+	for testName, chain := range tests {
+		var executor = &contextExecutor{}
+
+		assert.Nil(t, chain(ctx, executor, req, httptest.NewRecorder()), testName)
+		assert.Equal(t, `trace-id`, executor.value, testName)
+	}
+}
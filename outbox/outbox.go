@@ -0,0 +1,253 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/sepuka/vkbotserver/api"
+	"github.com/sepuka/vkbotserver/api/button"
+	"github.com/sepuka/vkbotserver/config"
+	"github.com/sepuka/vkbotserver/errors"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+const (
+	StatePending State = `pending`
+	StateFailed  State = `failed`
+
+	defaultInterval = time.Second
+)
+
+type (
+	State string
+
+	// Message is the outgoing message, Keyboard is optional
+	Message struct {
+		PeerId     int
+		Text       string
+		Attachment string
+		Keyboard   *button.Keyboard
+	}
+
+	// Entry is the persisted message, RandomId is sent with every attempt so VK drops duplicates
+	Entry struct {
+		RandomId    int64     `json:"random_id"`
+		PeerId      int       `json:"peer_id"`
+		Text        string    `json:"text"`
+		Attachment  string    `json:"attachment,omitempty"`
+		Keyboard    string    `json:"keyboard,omitempty"`
+		State       State     `json:"state"`
+		Attempts    int       `json:"attempts"`
+		NextAttempt time.Time `json:"next_attempt"`
+		LastError   string    `json:"last_error,omitempty"`
+		CreatedAt   time.Time `json:"created_at"`
+	}
+
+	// Store keeps entries until they're delivered. Pending returns pending entries in order of creation,
+	// failed entries stay in the store for inspection
+	Store interface {
+		Add(ctx context.Context, entry *Entry) error
+		Pending(ctx context.Context) ([]Entry, error)
+		Update(ctx context.Context, entry *Entry) error
+		Remove(ctx context.Context, randomId int64) error
+	}
+
+	// Outbox persists messages before sending and delivers them by the background dispatcher,
+	// the entry is removed only after VK confirms the message, so messages are delivered at least once
+	Outbox struct {
+		api      *api.Api
+		logger   *zap.SugaredLogger
+		store    Store
+		rnd      api.Rnder
+		cfg      config.Outbox
+		now      func() time.Time
+		notify   chan struct{}
+		dispatch sync.Mutex
+	}
+)
+
+// NewOutbox creates outbox, Run must be called to deliver messages
+func NewOutbox(vk *api.Api, logger *zap.SugaredLogger, store Store, rnd api.Rnder, cfg config.Outbox) *Outbox {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultInterval
+	}
+
+	return &Outbox{
+		api:    vk,
+		logger: logger,
+		store:  store,
+		rnd:    rnd,
+		cfg:    cfg,
+		now:    time.Now,
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// Send persists the message and wakes the dispatcher up, the message is going to be delivered once Send returns no error
+func (o *Outbox) Send(ctx context.Context, msg Message) (Entry, error) {
+	var (
+		now   = o.now()
+		entry = Entry{
+			RandomId:    o.rnd.Rnd(),
+			PeerId:      msg.PeerId,
+			Text:        msg.Text,
+			Attachment:  msg.Attachment,
+			State:       StatePending,
+			NextAttempt: now,
+			CreatedAt:   now,
+		}
+		js  []byte
+		err error
+	)
+
+	if msg.Keyboard != nil {
+		if js, err = json.Marshal(msg.Keyboard); err != nil {
+			return Entry{}, err
+		}
+
+		entry.Keyboard = string(js)
+	}
+
+	if err = o.store.Add(ctx, &entry); err != nil {
+		return Entry{}, err
+	}
+
+	select {
+	case o.notify <- struct{}{}:
+	default:
+	}
+
+	return entry, nil
+}
+
+// Run delivers due messages every interval and right after Send until ctx is done.
+// Messages left by the previous process are delivered on start
+func (o *Outbox) Run(ctx context.Context) error {
+	var ticker = time.NewTicker(o.cfg.Interval)
+
+	defer ticker.Stop()
+
+	for {
+		if err := o.Dispatch(ctx); err != nil && ctx.Err() == nil {
+			o.
+				logger.
+				With(zap.Error(err)).
+				Error(`outbox dispatch error`)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-o.notify:
+		}
+	}
+}
+
+// Dispatch sends due messages once. If the message of the peer is postponed,
+// next messages of the peer wait for it to keep their order
+func (o *Outbox) Dispatch(ctx context.Context) error {
+	var (
+		now       = o.now()
+		entries   []Entry
+		postponed = map[int]bool{}
+		delivered bool
+		err       error
+	)
+
+	o.dispatch.Lock()
+	defer o.dispatch.Unlock()
+
+	if entries, err = o.store.Pending(ctx); err != nil {
+		return err
+	}
+
+	for i := range entries {
+		var entry = &entries[i]
+
+		if postponed[entry.PeerId] {
+			continue
+		}
+
+		if entry.NextAttempt.After(now) {
+			postponed[entry.PeerId] = true
+			continue
+		}
+
+		if delivered, err = o.deliver(ctx, entry); err != nil {
+			return err
+		}
+
+		postponed[entry.PeerId] = !delivered && entry.State == StatePending
+	}
+
+	return nil
+}
+
+// deliver sends the entry and removes it, a failed entry is updated with the next attempt or marked failed
+func (o *Outbox) deliver(ctx context.Context, entry *Entry) (bool, error) {
+	var (
+		msg = api.OutcomeMessage{
+			Message:    entry.Text,
+			Attachment: entry.Attachment,
+			Keyboard:   entry.Keyboard,
+			RandomId:   entry.RandomId,
+		}
+		results []api.SendResult
+		err     error
+	)
+
+	if results, err = o.api.SendMessageToPeersContext(ctx, []int{entry.PeerId}, msg); err == nil && results[0].Error != nil {
+		err = results[0].Error
+	}
+
+	if err == nil {
+		return true, o.store.Remove(ctx, entry.RandomId)
+	}
+
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	entry.Attempts++
+	entry.LastError = err.Error()
+
+	// authorization errors are repeated as the token may be fixed without losing messages
+	switch {
+	case errors.IsPermanent(err):
+		entry.State = StateFailed
+	case o.cfg.MaxAttempts > 0 && entry.Attempts >= o.cfg.MaxAttempts:
+		entry.State = StateFailed
+	default:
+		entry.NextAttempt = o.now().Add(o.backoff(entry.Attempts))
+	}
+
+	o.
+		logger.
+		With(
+			zap.Int(`peer_id`, entry.PeerId),
+			zap.Int64(`random_id`, entry.RandomId),
+			zap.Int(`attempts`, entry.Attempts),
+			zap.String(`state`, string(entry.State)),
+			zap.Error(err),
+		).
+		Error(`outbox delivery error`)
+
+	return false, o.store.Update(ctx, entry)
+}
+
+// backoff doubles the delay for every next attempt up to MaxBackoff
+func (o *Outbox) backoff(attempts int) time.Duration {
+	var backoff = o.cfg.Backoff
+
+	for i := 1; i < attempts && (o.cfg.MaxBackoff <= 0 || backoff < o.cfg.MaxBackoff); i++ {
+		backoff *= 2
+	}
+
+	if o.cfg.MaxBackoff > 0 && backoff > o.cfg.MaxBackoff {
+		backoff = o.cfg.MaxBackoff
+	}
+
+	return backoff
+}
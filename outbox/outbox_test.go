@@ -0,0 +1,173 @@
+package outbox
+
+import (
+	"context"
+	"github.com/sepuka/vkbotserver/api"
+	"github.com/sepuka/vkbotserver/api/apitest"
+	"github.com/sepuka/vkbotserver/api/mocks"
+	"github.com/sepuka/vkbotserver/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+const (
+	sent        = `{"response":[{"peer_id":557404793,"message_id":1}]}`
+	internal    = `{"error":{"error_code":10,"error_msg":"Internal server error"}}`
+	notAllowed  = `{"response":[{"peer_id":557404793,"error":{"error_code":901,"error_msg":"Can't send messages for users without permission"}}]}`
+	testPeerId  = 557404793
+	otherPeerId = 2000000001
+)
+
+func newOutbox(server *apitest.Server, store Store, cfg config.Outbox, randomIds ...int64) (*Outbox, *time.Time) {
+	var (
+		rnd    = &mocks.Rnder{}
+		vk     = api.NewApi(zap.NewNop().Sugar(), config.Config{}, server, rnd)
+		outbox = NewOutbox(vk, zap.NewNop().Sugar(), store, rnd, cfg)
+		now    = time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	)
+
+	for _, randomId := range randomIds {
+		rnd.On(`Rnd`).Once().Return(randomId)
+	}
+
+	outbox.now = func() time.Time {
+		return now
+	}
+
+	return outbox, &now
+}
+
+func TestOutbox_Dispatch(t *testing.T) {
+	var (
+		server   = apitest.NewQueueServer(sent)
+		store, _ = NewFileStore(t.TempDir())
+		outbox   *Outbox
+		ctx      = context.Background()
+	)
+
+	outbox, _ = newOutbox(server, store, config.Outbox{}, 11, 12)
+
+	_, err := outbox.Send(ctx, Message{PeerId: testPeerId, Text: `first`})
+	assert.Nil(t, err)
+	_, err = outbox.Send(ctx, Message{PeerId: otherPeerId, Text: `second`})
+	assert.Nil(t, err)
+
+	assert.Nil(t, outbox.Dispatch(ctx))
+
+	assert.Len(t, server.Forms(), 2)
+	assert.Equal(t, `11`, server.Forms()[0].Get(`random_id`))
+	assert.Equal(t, `first`, server.Forms()[0].Get(`message`))
+	assert.Equal(t, `557404793`, server.Forms()[0].Get(`peer_ids`))
+	assert.Equal(t, `12`, server.Forms()[1].Get(`random_id`))
+
+	entries, _ := store.Pending(ctx)
+	assert.Empty(t, entries, `delivered messages are removed`)
+}
+
+func TestOutbox_DispatchRetries(t *testing.T) {
+	var (
+		server      = apitest.NewQueueServer(sent, internal)
+		store       = NewMemoryStore()
+		cfg         = config.Outbox{Backoff: time.Second, MaxBackoff: time.Minute}
+		outbox, now = newOutbox(server, store, cfg, 21, 22)
+		ctx         = context.Background()
+	)
+
+	_, _ = outbox.Send(ctx, Message{PeerId: testPeerId, Text: `first`})
+	*now = now.Add(time.Millisecond)
+	_, _ = outbox.Send(ctx, Message{PeerId: testPeerId, Text: `second`})
+
+	assert.Nil(t, outbox.Dispatch(ctx))
+	assert.Len(t, server.Forms(), 1, `the next message of the peer waits for the postponed one`)
+
+	entries, _ := store.Pending(ctx)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, 1, entries[0].Attempts)
+	assert.Equal(t, now.Add(time.Second), entries[0].NextAttempt)
+	assert.Contains(t, entries[0].LastError, `Internal server error`)
+
+	assert.Nil(t, outbox.Dispatch(ctx))
+	assert.Len(t, server.Forms(), 1, `the message is not repeated before the backoff`)
+
+	*now = now.Add(time.Second)
+	assert.Nil(t, outbox.Dispatch(ctx))
+
+	assert.Len(t, server.Forms(), 3)
+	assert.Equal(t, `21`, server.Forms()[1].Get(`random_id`), `the repeated message keeps its random_id`)
+	assert.Equal(t, `second`, server.Forms()[2].Get(`message`))
+}
+
+func TestOutbox_DispatchFails(t *testing.T) {
+	var (
+		tests = map[string]struct {
+			bodies   []string
+			cfg      config.Outbox
+			attempts int
+		}{
+			`rejected by VK`: {
+				bodies:   []string{notAllowed},
+				attempts: 1,
+			},
+			`attempts exceeded`: {
+				bodies:   []string{internal, internal},
+				cfg:      config.Outbox{MaxAttempts: 2},
+				attempts: 2,
+			},
+		}
+	)
+
+	for testName, testCase := range tests {
+		var (
+			server    = apitest.NewQueueServer(sent, testCase.bodies...)
+			store     = NewMemoryStore()
+			outbox, _ = newOutbox(server, store, testCase.cfg, 31)
+			ctx       = context.Background()
+		)
+
+		_, _ = outbox.Send(ctx, Message{PeerId: testPeerId, Text: `hello`})
+
+		for i := 0; i < 3; i++ {
+			assert.Nil(t, outbox.Dispatch(ctx), testName)
+		}
+
+		assert.Len(t, server.Forms(), testCase.attempts, testName)
+		assert.Equal(t, StateFailed, store.entries[31].State, testName)
+		assert.Equal(t, testCase.attempts, store.entries[31].Attempts, testName)
+	}
+}
+
+func TestOutbox_RunDeliversLeftMessages(t *testing.T) {
+	var (
+		server      = apitest.NewQueueServer(sent)
+		dir         = t.TempDir()
+		store, _    = NewFileStore(dir)
+		left        = &Entry{RandomId: 41, PeerId: testPeerId, Text: `left`, State: StatePending}
+		ctx, cancel = context.WithCancel(context.Background())
+		done        = make(chan error)
+	)
+
+	assert.Nil(t, store.Add(ctx, left))
+
+	store, _ = NewFileStore(dir)
+	outbox, _ := newOutbox(server, store, config.Outbox{Interval: time.Hour}, 42)
+
+	go func() {
+		done <- outbox.Run(ctx)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return len(server.Forms()) == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, `41`, server.Forms()[0].Get(`random_id`))
+
+	_, _ = outbox.Send(ctx, Message{PeerId: testPeerId, Text: `new`})
+
+	assert.Eventually(t, func() bool {
+		return len(server.Forms()) == 2
+	}, time.Second, time.Millisecond, `Send wakes the dispatcher up`)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
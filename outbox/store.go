@@ -0,0 +1,195 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+const (
+	entryFileExt = `.json`
+)
+
+type (
+	memoryStore struct {
+		mu      sync.Mutex
+		entries map[int64]Entry
+	}
+
+	fileStore struct {
+		mu  sync.Mutex
+		dir string
+	}
+)
+
+// NewMemoryStore creates store which keeps entries in the process memory, they're lost on crash
+func NewMemoryStore() *memoryStore {
+	return &memoryStore{
+		entries: map[int64]Entry{},
+	}
+}
+
+func (s *memoryStore) Add(ctx context.Context, entry *Entry) error {
+	return s.Update(ctx, entry)
+}
+
+func (s *memoryStore) Pending(ctx context.Context) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var entries = make([]Entry, 0, len(s.entries))
+
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+
+	return pending(entries), nil
+}
+
+func (s *memoryStore) Update(ctx context.Context, entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[entry.RandomId] = *entry
+
+	return nil
+}
+
+func (s *memoryStore) Remove(ctx context.Context, randomId int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, randomId)
+
+	return nil
+}
+
+// NewFileStore creates store which keeps each entry as a JSON file in the dir, the dir is created if it's missing
+func NewFileStore(dir string) (*fileStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	return &fileStore{
+		dir: dir,
+	}, nil
+}
+
+func (s *fileStore) Add(ctx context.Context, entry *Entry) error {
+	return s.Update(ctx, entry)
+}
+
+func (s *fileStore) Pending(ctx context.Context) ([]Entry, error) {
+	var (
+		files   []os.FileInfo
+		entries []Entry
+		err     error
+	)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if files, err = ioutil.ReadDir(s.dir); err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		var (
+			entry Entry
+			data  []byte
+		)
+
+		if file.IsDir() || filepath.Ext(file.Name()) != entryFileExt {
+			continue
+		}
+
+		if data, err = ioutil.ReadFile(filepath.Join(s.dir, file.Name())); err != nil {
+			return nil, err
+		}
+
+		if err = json.Unmarshal(data, &entry); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return pending(entries), nil
+}
+
+// Update writes the entry to a temporary file and renames it, so a crash never leaves a partially written entry
+func (s *fileStore) Update(ctx context.Context, entry *Entry) error {
+	var (
+		data []byte
+		tmp  *os.File
+		err  error
+	)
+
+	if data, err = json.Marshal(entry); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if tmp, err = ioutil.TempFile(s.dir, `entry-*.tmp`); err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path(entry.RandomId))
+}
+
+func (s *fileStore) Remove(ctx context.Context, randomId int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(randomId)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *fileStore) path(randomId int64) string {
+	return filepath.Join(s.dir, strconv.FormatInt(randomId, 10)+entryFileExt)
+}
+
+// pending filters pending entries and sorts them by creation
+func pending(entries []Entry) []Entry {
+	var result = make([]Entry, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.State == StatePending {
+			result = append(result, entry)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].RandomId < result[j].RandomId
+		}
+
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result
+}
@@ -0,0 +1,145 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// Clock tells the time to the scheduler, FakeClock lets tests move the time deterministically
+	Clock interface {
+		Now() time.Time
+		NewTimer(d time.Duration) Timer
+	}
+
+	// Timer fires once on C unless it's stopped
+	Timer interface {
+		C() <-chan time.Time
+		Stop() bool
+	}
+
+	realClock struct {
+	}
+
+	realTimer struct {
+		timer *time.Timer
+	}
+
+	// stoppedTimer never fires, the scheduler waits on it when there are no jobs
+	stoppedTimer struct {
+	}
+
+	// FakeClock stands still until Advance is called
+	FakeClock struct {
+		mu     sync.Mutex
+		now    time.Time
+		timers map[*fakeTimer]struct{}
+	}
+
+	fakeTimer struct {
+		clock    *FakeClock
+		deadline time.Time
+		c        chan time.Time
+	}
+)
+
+// NewClock returns the system clock
+func NewClock() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{timer: time.NewTimer(d)}
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t realTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+func (stoppedTimer) C() <-chan time.Time {
+	return nil
+}
+
+func (stoppedTimer) Stop() bool {
+	return false
+}
+
+// NewFakeClock creates clock showing now
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{
+		now:    now,
+		timers: map[*fakeTimer]struct{}{},
+	}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var timer = &fakeTimer{
+		clock:    c,
+		deadline: c.now.Add(d),
+		c:        make(chan time.Time, 1),
+	}
+
+	if d <= 0 {
+		timer.c <- c.now
+	} else {
+		c.timers[timer] = struct{}{}
+	}
+
+	return timer
+}
+
+// Advance moves the time forward and fires timers whose deadline has come
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	for timer := range c.timers {
+		if !timer.deadline.After(c.now) {
+			timer.c <- c.now
+			delete(c.timers, timer)
+		}
+	}
+}
+
+// Waiters returns the number of timers waiting for Advance, tests use it to know the scheduler is asleep
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.timers)
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	var _, waiting = t.clock.timers[t]
+
+	delete(t.clock.timers, t)
+
+	return waiting
+}
@@ -0,0 +1,180 @@
+package scheduler
+
+import (
+	"fmt"
+	"github.com/sepuka/vkbotserver/errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronLookahead limits the search of the next run of schedules like "0 0 30 2 *" which never happen
+const cronLookahead = 5
+
+var cronMacros = map[string]string{
+	`@hourly`:  `0 * * * *`,
+	`@daily`:   `0 0 * * *`,
+	`@weekly`:  `0 0 * * 0`,
+	`@monthly`: `0 0 1 * *`,
+	`@yearly`:  `0 0 1 1 *`,
+}
+
+type (
+	// Schedule is the parsed cron expression of five fields: minute, hour, day of month, month and day of week
+	Schedule struct {
+		minute, hour, dom, month, dow cronField
+		// as in cron, the day matches either field if both of them are restricted
+		domAny, dowAny bool
+	}
+
+	cronField uint64
+
+	cronBounds struct {
+		name     string
+		min, max int
+	}
+)
+
+var (
+	minuteBounds = cronBounds{name: `minute`, min: 0, max: 59}
+	hourBounds   = cronBounds{name: `hour`, min: 0, max: 23}
+	domBounds    = cronBounds{name: `day of month`, min: 1, max: 31}
+	monthBounds  = cronBounds{name: `month`, min: 1, max: 12}
+	dowBounds    = cronBounds{name: `day of week`, min: 0, max: 7}
+)
+
+// ParseSchedule parses cron expressions like "30 9 * * 1-5" or "*/15 * * * *" and macros like @daily.
+// Fields support numbers, *, lists, ranges and steps, 7 is Sunday as well as 0
+func ParseSchedule(spec string) (*Schedule, error) {
+	var (
+		schedule = &Schedule{}
+		fields   []string
+		err      error
+	)
+
+	if macro, ok := cronMacros[strings.TrimSpace(spec)]; ok {
+		spec = macro
+	}
+
+	if fields = strings.Fields(spec); len(fields) != 5 {
+		return nil, errors.NewInvalidScheduleError(fmt.Sprintf(`"%s" must have 5 fields`, spec))
+	}
+
+	if schedule.minute, err = parseCronField(fields[0], minuteBounds); err != nil {
+		return nil, err
+	}
+
+	if schedule.hour, err = parseCronField(fields[1], hourBounds); err != nil {
+		return nil, err
+	}
+
+	if schedule.dom, err = parseCronField(fields[2], domBounds); err != nil {
+		return nil, err
+	}
+
+	if schedule.month, err = parseCronField(fields[3], monthBounds); err != nil {
+		return nil, err
+	}
+
+	if schedule.dow, err = parseCronField(fields[4], dowBounds); err != nil {
+		return nil, err
+	}
+
+	if schedule.dow.has(7) {
+		schedule.dow |= 1
+	}
+
+	schedule.domAny, schedule.dowAny = strings.HasPrefix(fields[2], `*`), strings.HasPrefix(fields[4], `*`)
+
+	return schedule, nil
+}
+
+// Next returns the first run after t in the location of t or zero time if the schedule never happens
+func (s *Schedule) Next(t time.Time) time.Time {
+	var (
+		next  = t.Truncate(time.Minute).Add(time.Minute)
+		limit = next.AddDate(cronLookahead, 0, 0)
+		loc   = t.Location()
+	)
+
+	for next.Before(limit) {
+		switch {
+		case !s.month.has(int(next.Month())):
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.day(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, loc)
+		case !s.hour.has(next.Hour()):
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, loc)
+		case !s.minute.has(next.Minute()):
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+
+	return time.Time{}
+}
+
+func (s *Schedule) day(t time.Time) bool {
+	var (
+		dom = s.dom.has(t.Day())
+		dow = s.dow.has(int(t.Weekday()))
+	)
+
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+
+	return dom || dow
+}
+
+func (f cronField) has(value int) bool {
+	return f&(1<<uint(value)) != 0
+}
+
+func parseCronField(field string, bounds cronBounds) (cronField, error) {
+	var result cronField
+
+	for _, part := range strings.Split(field, `,`) {
+		var (
+			from, to = bounds.min, bounds.max
+			step     = 1
+			rng      = part
+			err      error
+		)
+
+		if i := strings.Index(part, `/`); i >= 0 {
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, errors.NewInvalidScheduleError(fmt.Sprintf(`invalid step "%s" of %s`, part, bounds.name))
+			}
+
+			rng = part[:i]
+		}
+
+		if rng != `*` {
+			var bound = strings.SplitN(rng, `-`, 2)
+
+			if from, err = strconv.Atoi(bound[0]); err != nil {
+				return 0, errors.NewInvalidScheduleError(fmt.Sprintf(`invalid %s "%s"`, bounds.name, part))
+			}
+
+			if to = from; len(bound) == 2 {
+				if to, err = strconv.Atoi(bound[1]); err != nil {
+					return 0, errors.NewInvalidScheduleError(fmt.Sprintf(`invalid %s "%s"`, bounds.name, part))
+				}
+			} else if rng != part {
+				to = bounds.max
+			}
+		}
+
+		if from < bounds.min || to > bounds.max || from > to {
+			return 0, errors.NewInvalidScheduleError(fmt.Sprintf(`%s "%s" is out of %d-%d`, bounds.name, part, bounds.min, bounds.max))
+		}
+
+		for value := from; value <= to; value += step {
+			result |= 1 << uint(value)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"github.com/sepuka/vkbotserver/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	var (
+		// Wednesday
+		now   = time.Date(2022, 6, 1, 12, 30, 15, 0, time.UTC)
+		tests = map[string]struct {
+			spec     string
+			expected time.Time
+		}{
+			`every minute`: {
+				spec:     `* * * * *`,
+				expected: time.Date(2022, 6, 1, 12, 31, 0, 0, time.UTC),
+			},
+			`every 15 minutes`: {
+				spec:     `*/15 * * * *`,
+				expected: time.Date(2022, 6, 1, 12, 45, 0, 0, time.UTC),
+			},
+			`daily earlier than now`: {
+				spec:     `@daily`,
+				expected: time.Date(2022, 6, 2, 0, 0, 0, 0, time.UTC),
+			},
+			`working days`: {
+				spec:     `30 9 * * 1-5`,
+				expected: time.Date(2022, 6, 2, 9, 30, 0, 0, time.UTC),
+			},
+			`sunday as 7`: {
+				spec:     `0 10 * * 7`,
+				expected: time.Date(2022, 6, 5, 10, 0, 0, 0, time.UTC),
+			},
+			`list of hours`: {
+				spec:     `0 8,20 * * *`,
+				expected: time.Date(2022, 6, 1, 20, 0, 0, 0, time.UTC),
+			},
+			`day of month or day of week`: {
+				spec:     `0 0 15 * 5`,
+				expected: time.Date(2022, 6, 3, 0, 0, 0, 0, time.UTC),
+			},
+			`next year`: {
+				spec:     `0 0 1 1 *`,
+				expected: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			`leap day`: {
+				spec:     `0 0 29 2 *`,
+				expected: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			},
+			`never`: {
+				spec: `0 0 30 2 *`,
+			},
+		}
+	)
+
+	for testName, testCase := range tests {
+		schedule, err := ParseSchedule(testCase.spec)
+
+		assert.Nil(t, err, testName)
+		assert.Equal(t, testCase.expected, schedule.Next(now), testName)
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	var tests = map[string]string{
+		`too few fields`:    `* * * *`,
+		`out of range`:      `60 * * * *`,
+		`reversed range`:    `* 10-5 * * *`,
+		`zero step`:         `*/0 * * * *`,
+		`not a number`:      `* * * jan *`,
+		`zero day of month`: `0 0 0 * *`,
+	}
+
+	for testName, spec := range tests {
+		_, err := ParseSchedule(spec)
+
+		assert.ErrorIs(t, err, errors.InvalidSchedule, testName)
+	}
+}
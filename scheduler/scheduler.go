@@ -0,0 +1,347 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/sepuka/vkbotserver/api"
+	"github.com/sepuka/vkbotserver/api/button"
+	"github.com/sepuka/vkbotserver/errors"
+	"go.uber.org/zap"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const defaultRetryDelay = time.Minute
+
+type (
+	// Message is sent to the peer when the job runs, Keyboard is optional
+	Message struct {
+		PeerId     int
+		Text       string
+		Attachment string
+		Keyboard   *button.Keyboard
+	}
+
+	// Job is the scheduled message, At is the time of the next run. Run is the scheduled time of the run,
+	// unlike At it isn't moved by retries. Recurring jobs have the cron Schedule
+	Job struct {
+		Id         string    `json:"id"`
+		PeerId     int       `json:"peer_id"`
+		Text       string    `json:"text"`
+		Attachment string    `json:"attachment,omitempty"`
+		Keyboard   string    `json:"keyboard,omitempty"`
+		At         time.Time `json:"at"`
+		Run        time.Time `json:"run"`
+		Schedule   string    `json:"schedule,omitempty"`
+		CreatedAt  time.Time `json:"created_at"`
+	}
+
+	// Store keeps jobs until they're done or cancelled, Delete of an unknown job is not an error
+	Store interface {
+		Save(ctx context.Context, job *Job) error
+		Delete(ctx context.Context, id string) error
+		List(ctx context.Context) ([]Job, error)
+	}
+
+	// Scheduler sends messages at the given time, after a delay or by a cron schedule
+	Scheduler struct {
+		api        *api.Api
+		logger     *zap.SugaredLogger
+		store      Store
+		clock      Clock
+		mu         sync.Mutex
+		running    map[string]bool
+		wake       chan struct{}
+		retryDelay time.Duration
+	}
+)
+
+// NewScheduler creates scheduler, Run must be called to send messages
+func NewScheduler(vk *api.Api, logger *zap.SugaredLogger, store Store, clock Clock) *Scheduler {
+	return &Scheduler{
+		api:        vk,
+		logger:     logger,
+		store:      store,
+		clock:      clock,
+		running:    map[string]bool{},
+		wake:       make(chan struct{}, 1),
+		retryDelay: defaultRetryDelay,
+	}
+}
+
+// At schedules the message at the given time and returns the job id
+func (s *Scheduler) At(ctx context.Context, at time.Time, msg Message) (string, error) {
+	return s.add(ctx, at, ``, msg)
+}
+
+// After schedules the message after the delay and returns the job id
+func (s *Scheduler) After(ctx context.Context, delay time.Duration, msg Message) (string, error) {
+	return s.add(ctx, s.clock.Now().Add(delay), ``, msg)
+}
+
+// Every schedules the message by the cron expression like "0 9 * * 1-5" and returns the job id
+func (s *Scheduler) Every(ctx context.Context, spec string, msg Message) (string, error) {
+	var (
+		schedule *Schedule
+		at       time.Time
+		err      error
+	)
+
+	if schedule, err = ParseSchedule(spec); err != nil {
+		return ``, err
+	}
+
+	if at = schedule.Next(s.clock.Now()); at.IsZero() {
+		return ``, errors.NewInvalidScheduleError(fmt.Sprintf(`"%s" never happens`, spec))
+	}
+
+	return s.add(ctx, at, spec, msg)
+}
+
+// Cancel removes the job, the job being run right now isn't sent again or rescheduled
+func (s *Scheduler) Cancel(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.store.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if _, ok := s.running[id]; ok {
+		s.running[id] = true
+	}
+
+	s.notify()
+
+	return nil
+}
+
+// Run sends messages of due jobs until ctx is done. Jobs missed while the process was stopped run once on start,
+// recurring jobs continue by their schedule from now
+func (s *Scheduler) Run(ctx context.Context) error {
+	for {
+		var (
+			next  time.Time
+			timer Timer
+			err   error
+		)
+
+		if next, err = s.runDue(ctx); err != nil && ctx.Err() == nil {
+			s.
+				logger.
+				With(zap.Error(err)).
+				Error(`scheduler error`)
+
+			next = s.clock.Now().Add(s.retryDelay)
+		}
+
+		if !next.IsZero() {
+			timer = s.clock.NewTimer(next.Sub(s.clock.Now()))
+		} else {
+			timer = stoppedTimer{}
+		}
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C():
+		case <-s.wake:
+			timer.Stop()
+		}
+	}
+}
+
+// runDue runs due jobs and returns the time of the nearest next run, zero if there are no jobs.
+// Due jobs are marked as running, so Cancel called while they're sent keeps them cancelled
+func (s *Scheduler) runDue(ctx context.Context) (time.Time, error) {
+	var (
+		now  = s.clock.Now()
+		next time.Time
+		jobs []Job
+		err  error
+	)
+
+	s.mu.Lock()
+	if jobs, err = s.store.List(ctx); err == nil {
+		for _, job := range jobs {
+			if !job.At.After(now) {
+				s.running[job.Id] = false
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	defer s.release(jobs)
+
+	for i := range jobs {
+		var job = &jobs[i]
+
+		if !job.At.After(now) {
+			if err = s.run(ctx, job, now); err != nil {
+				return time.Time{}, err
+			}
+
+			if job.At.IsZero() {
+				continue
+			}
+		}
+
+		if next.IsZero() || job.At.Before(next) {
+			next = job.At
+		}
+	}
+
+	return next, nil
+}
+
+// run sends the message and moves the job to its next run or deletes it, At of the deleted job is zero.
+// The random_id depends on the job and the run, so VK drops the message sent again after a crash or a retry.
+// The lock is held only around the store writes, so a slow call doesn't block Cancel
+func (s *Scheduler) run(ctx context.Context, job *Job, now time.Time) error {
+	var (
+		msg = api.OutcomeMessage{
+			Message:    job.Text,
+			Attachment: job.Attachment,
+			Keyboard:   job.Keyboard,
+			RandomId:   api.StableRandomId(job.Id, strconv.FormatInt(job.Run.Unix(), 10)),
+		}
+		results []api.SendResult
+		err     error
+	)
+
+	if s.cancelled(job.Id) {
+		job.At = time.Time{}
+
+		return nil
+	}
+
+	if results, err = s.api.SendMessageToPeersContext(ctx, []int{job.PeerId}, msg); err == nil && results[0].Error != nil {
+		err = results[0].Error
+	}
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		s.
+			logger.
+			With(
+				zap.String(`job`, job.Id),
+				zap.Int(`peer_id`, job.PeerId),
+				zap.Error(err),
+			).
+			Error(`scheduled message error`)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running[job.Id] {
+		job.At = time.Time{}
+
+		return nil
+	}
+
+	return s.reschedule(ctx, job, now, err)
+}
+
+// reschedule moves the job to the retry or the next run or deletes it depending on the result of the run
+func (s *Scheduler) reschedule(ctx context.Context, job *Job, now time.Time, err error) error {
+	var schedule *Schedule
+
+	if err != nil && !errors.IsPermanent(err) {
+		job.At = now.Add(s.retryDelay)
+
+		return s.store.Save(ctx, job)
+	}
+
+	if job.Schedule != `` {
+		if schedule, err = ParseSchedule(job.Schedule); err != nil {
+			return err
+		}
+
+		if job.At = schedule.Next(now); !job.At.IsZero() {
+			job.Run = job.At
+
+			return s.store.Save(ctx, job)
+		}
+	}
+
+	job.At = time.Time{}
+
+	return s.store.Delete(ctx, job.Id)
+}
+
+// cancelled tells whether the running job was cancelled
+func (s *Scheduler) cancelled(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.running[id]
+}
+
+// release forgets the jobs run by runDue
+func (s *Scheduler) release(jobs []Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, job := range jobs {
+		delete(s.running, job.Id)
+	}
+}
+
+func (s *Scheduler) add(ctx context.Context, at time.Time, spec string, msg Message) (string, error) {
+	var (
+		job = &Job{
+			PeerId:     msg.PeerId,
+			Text:       msg.Text,
+			Attachment: msg.Attachment,
+			At:         at,
+			Run:        at,
+			Schedule:   spec,
+			CreatedAt:  s.clock.Now(),
+		}
+		id  = make([]byte, 8)
+		js  []byte
+		err error
+	)
+
+	if _, err = rand.Read(id); err != nil {
+		return ``, err
+	}
+
+	job.Id = hex.EncodeToString(id)
+
+	if msg.Keyboard != nil {
+		if js, err = json.Marshal(msg.Keyboard); err != nil {
+			return ``, err
+		}
+
+		job.Keyboard = string(js)
+	}
+
+	if err = s.store.Save(ctx, job); err != nil {
+		return ``, err
+	}
+
+	s.notify()
+
+	return job.Id, nil
+}
+
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
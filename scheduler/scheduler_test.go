@@ -0,0 +1,195 @@
+package scheduler
+
+import (
+	"context"
+	"github.com/sepuka/vkbotserver/api"
+	"github.com/sepuka/vkbotserver/api/apitest"
+	"github.com/sepuka/vkbotserver/api/mocks"
+	"github.com/sepuka/vkbotserver/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const (
+	sent     = `{"response":[{"peer_id":557404793,"message_id":1}]}`
+	internal = `{"error":{"error_code":10,"error_msg":"Internal server error"}}`
+)
+
+// messages returns texts of the sent messages
+func messages(server *apitest.Server) []string {
+	var texts []string
+
+	for _, form := range server.Forms() {
+		texts = append(texts, form.Get(`message`))
+	}
+
+	return texts
+}
+
+func newScheduler(server *apitest.Server, store Store, clock Clock) *Scheduler {
+	var vk = api.NewApi(zap.NewNop().Sugar(), config.Config{}, server, &mocks.Rnder{})
+
+	return NewScheduler(vk, zap.NewNop().Sugar(), store, clock)
+}
+
+// run starts the scheduler and returns the function stopping it
+func run(t *testing.T, scheduler *Scheduler) func() {
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		done        = make(chan error)
+	)
+
+	go func() {
+		done <- scheduler.Run(ctx)
+	}()
+
+	return func() {
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+	}
+}
+
+// advance moves the clock when the scheduler is asleep and waits for the expected messages
+func advance(t *testing.T, clock *FakeClock, server *apitest.Server, d time.Duration, expected ...string) {
+	assert.Eventually(t, func() bool {
+		return clock.Waiters() == 1
+	}, time.Second, time.Millisecond)
+
+	clock.Advance(d)
+
+	assert.Eventually(t, func() bool {
+		return len(server.Forms()) == len(expected)
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, expected, messages(server))
+}
+
+func TestScheduler_Run(t *testing.T) {
+	var (
+		server    = apitest.NewQueueServer(sent)
+		clock     = NewFakeClock(time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC))
+		scheduler = newScheduler(server, NewMemoryStore(), clock)
+		ctx       = context.Background()
+		stop      = run(t, scheduler)
+	)
+
+	defer stop()
+
+	_, err := scheduler.After(ctx, 10*time.Minute, Message{PeerId: 557404793, Text: `reminder`})
+	assert.Nil(t, err)
+	_, err = scheduler.Every(ctx, `0 13 * * *`, Message{PeerId: 557404793, Text: `daily`})
+	assert.Nil(t, err)
+	cancelled, err := scheduler.At(ctx, clock.Now().Add(30*time.Minute), Message{PeerId: 557404793, Text: `cancelled`})
+	assert.Nil(t, err)
+	assert.Nil(t, scheduler.Cancel(ctx, cancelled))
+
+	advance(t, clock, server, 9*time.Minute)
+	advance(t, clock, server, time.Minute, `reminder`)
+	advance(t, clock, server, 50*time.Minute, `reminder`, `daily`)
+	advance(t, clock, server, 24*time.Hour, `reminder`, `daily`, `daily`)
+}
+
+func TestScheduler_RunMissedJobs(t *testing.T) {
+	var (
+		server = apitest.NewQueueServer(sent)
+		store  = NewMemoryStore()
+		clock  = NewFakeClock(time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC))
+		ctx    = context.Background()
+	)
+
+	_ = store.Save(ctx, &Job{Id: `once`, PeerId: 557404793, Text: `once`, At: clock.Now().Add(-time.Hour)})
+	_ = store.Save(ctx, &Job{Id: `hourly`, PeerId: 557404793, Text: `hourly`, At: clock.Now().Add(-3 * time.Hour), Schedule: `@hourly`})
+
+	var stop = run(t, newScheduler(server, store, clock))
+
+	assert.Eventually(t, func() bool {
+		return len(server.Forms()) == 2 && clock.Waiters() == 1
+	}, time.Second, time.Millisecond, `missed jobs run once`)
+	stop()
+
+	jobs, _ := store.List(ctx)
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, time.Date(2022, 6, 1, 13, 0, 0, 0, time.UTC), jobs[0].At, `the recurring job continues from now`)
+}
+
+func TestScheduler_RunRetries(t *testing.T) {
+	var (
+		server    = apitest.NewQueueServer(sent, internal)
+		store     = NewMemoryStore()
+		clock     = NewFakeClock(time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC))
+		scheduler = newScheduler(server, store, clock)
+		stop      = run(t, scheduler)
+	)
+
+	defer stop()
+
+	_, _ = scheduler.After(context.Background(), time.Minute, Message{PeerId: 557404793, Text: `reminder`})
+
+	advance(t, clock, server, time.Minute, `reminder`)
+	advance(t, clock, server, defaultRetryDelay, `reminder`, `reminder`)
+	assert.Equal(t, server.Forms()[0].Get(`random_id`), server.Forms()[1].Get(`random_id`), `the retried run keeps its random_id`)
+
+	assert.Eventually(t, func() bool {
+		var jobs, _ = store.List(context.Background())
+
+		return len(jobs) == 0
+	}, time.Second, time.Millisecond, `the sent job is deleted`)
+}
+
+func TestScheduler_CancelWhileSending(t *testing.T) {
+	var (
+		started = make(chan struct{}, 1)
+		release = make(chan struct{})
+		server  = apitest.NewServer(func(url.Values) string {
+			started <- struct{}{}
+			<-release
+
+			return sent
+		})
+		store     = NewMemoryStore()
+		clock     = NewFakeClock(time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC))
+		scheduler = newScheduler(server, store, clock)
+		ctx       = context.Background()
+		cancelled = make(chan error)
+		stop      = run(t, scheduler)
+	)
+
+	defer stop()
+
+	id, _ := scheduler.Every(ctx, `@hourly`, Message{PeerId: 557404793, Text: `hourly`})
+
+	assert.Eventually(t, func() bool {
+		return clock.Waiters() == 1
+	}, time.Second, time.Millisecond)
+	clock.Advance(time.Hour)
+	<-started
+
+	go func() {
+		cancelled <- scheduler.Cancel(ctx, id)
+	}()
+
+	select {
+	case err := <-cancelled:
+		assert.Nil(t, err)
+	case <-time.After(time.Second):
+		t.Fatal(`Cancel must not wait for the message being sent`)
+	}
+
+	close(release)
+
+	assert.Eventually(t, func() bool {
+		return clock.Waiters() == 0 && len(server.Forms()) == 1
+	}, time.Second, time.Millisecond)
+
+	jobs, _ := store.List(ctx)
+	assert.Empty(t, jobs, `the cancelled recurring job is not rescheduled`)
+}
+
+func TestScheduler_EveryInvalid(t *testing.T) {
+	var scheduler = newScheduler(apitest.NewQueueServer(sent), NewMemoryStore(), NewClock())
+
+	_, err := scheduler.Every(context.Background(), `0 0 30 2 *`, Message{PeerId: 557404793, Text: `never`})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-redis/redis/v8"
+	"sync"
+)
+
+const (
+	jobsKey = `vkbot_server_scheduler_jobs`
+)
+
+type (
+	memoryStore struct {
+		mu   sync.Mutex
+		jobs map[string]Job
+	}
+
+	redisStore struct {
+		client *redis.Client
+	}
+)
+
+// NewMemoryStore creates store which keeps jobs in the process memory, they're lost on restart
+func NewMemoryStore() *memoryStore {
+	return &memoryStore{
+		jobs: map[string]Job{},
+	}
+}
+
+func (s *memoryStore) Save(ctx context.Context, job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs[job.Id] = *job
+
+	return nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, id)
+
+	return nil
+}
+
+func (s *memoryStore) List(ctx context.Context) ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var jobs = make([]Job, 0, len(s.jobs))
+
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// NewRedisStore creates store which keeps jobs in the redis hash as JSON by the job id
+func NewRedisStore(client *redis.Client) *redisStore {
+	return &redisStore{
+		client: client,
+	}
+}
+
+func (s *redisStore) Save(ctx context.Context, job *Job) error {
+	var data, err = json.Marshal(job)
+
+	if err != nil {
+		return err
+	}
+
+	return s.client.HSet(ctx, jobsKey, job.Id, data).Err()
+}
+
+func (s *redisStore) Delete(ctx context.Context, id string) error {
+	return s.client.HDel(ctx, jobsKey, id).Err()
+}
+
+func (s *redisStore) List(ctx context.Context) ([]Job, error) {
+	var (
+		stored map[string]string
+		jobs   []Job
+		err    error
+	)
+
+	if stored, err = s.client.HGetAll(ctx, jobsKey).Result(); err != nil {
+		return nil, err
+	}
+
+	jobs = make([]Job, 0, len(stored))
+
+	for _, data := range stored {
+		var job Job
+
+		if err = json.Unmarshal([]byte(data), &job); err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
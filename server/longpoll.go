@@ -0,0 +1,247 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/mailru/easyjson"
+	"github.com/pkg/errors"
+	"github.com/sepuka/vkbotserver/api"
+	"github.com/sepuka/vkbotserver/config"
+	"github.com/sepuka/vkbotserver/domain"
+	"github.com/sepuka/vkbotserver/message"
+	"github.com/sepuka/vkbotserver/middleware"
+	"go.uber.org/zap"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	longPollServerMethod = `groups.getLongPollServer`
+	longPollCheckTmpl    = `%s?act=a_check&key=%s&ts=%s&wait=%d`
+	defaultWait          = 25
+	retryDelay           = time.Second
+)
+
+// LongPollServer receives events via Bots Long Poll API
+type LongPollServer struct {
+	cfg      config.Config
+	logger   *zap.SugaredLogger
+	client   api.HTTPClient
+	vk       *api.Api
+	messages message.HandlerMap
+	handler  middleware.ContextHandlerFunc
+}
+
+// NewLongPollServer constructor
+func NewLongPollServer(
+	cfg config.Config,
+	messages message.HandlerMap,
+	handler middleware.HandlerFunc,
+	client api.HTTPClient,
+	logger *zap.SugaredLogger,
+) *LongPollServer {
+	return NewLongPollServerContext(cfg, messages, middleware.WithContext(handler), client, logger)
+}
+
+// NewLongPollServerContext creates long poll server with the context-aware handlers chain
+func NewLongPollServerContext(
+	cfg config.Config,
+	messages message.HandlerMap,
+	handler middleware.ContextHandlerFunc,
+	client api.HTTPClient,
+	logger *zap.SugaredLogger,
+) *LongPollServer {
+	return &LongPollServer{
+		cfg:      cfg,
+		logger:   logger,
+		client:   client,
+		vk:       api.NewApi(logger, cfg, client, api.NewRnder()),
+		messages: messages,
+		handler:  handler,
+	}
+}
+
+// Listen polls VK for new events until SIGINT or SIGTERM is caught
+func (s *LongPollServer) Listen() error {
+	var (
+		signals     = make(chan os.Signal, 1)
+		ctx, cancel = context.WithCancel(context.Background())
+	)
+
+	defer cancel()
+
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	defer func() {
+		_ = s.logger.Sync()
+	}()
+
+	return s.poll(ctx)
+}
+
+func (s *LongPollServer) poll(ctx context.Context) error {
+	var (
+		lp      *domain.LongPollServer
+		fresh   *domain.LongPollServer
+		updates *domain.LongPollUpdates
+		err     error
+	)
+
+	if lp, err = s.fetchServer(ctx); err != nil {
+		return err
+	}
+
+	for ctx.Err() == nil {
+		if updates, err = s.check(ctx, lp); err != nil {
+			s.sleep(ctx)
+			continue
+		}
+
+		switch updates.Failed {
+		case 0:
+			lp.Ts = updates.Ts
+			s.dispatch(ctx, updates.Updates)
+		case domain.LongPollFailedTs:
+			lp.Ts = updates.Ts
+		case domain.LongPollFailedKey, domain.LongPollFailedInfo:
+			if fresh, err = s.fetchServer(ctx); err != nil {
+				s.sleep(ctx)
+				continue
+			}
+
+			lp.Key = fresh.Key
+			lp.Server = fresh.Server
+			if updates.Failed == domain.LongPollFailedInfo {
+				lp.Ts = fresh.Ts
+			}
+		default:
+			s.
+				logger.
+				With(zap.Int(`failed`, updates.Failed)).
+				Error(`unknown long poll failure code`)
+
+			s.sleep(ctx)
+		}
+	}
+
+	return nil
+}
+
+func (s *LongPollServer) fetchServer(ctx context.Context) (*domain.LongPollServer, error) {
+	var (
+		params = url.Values{`group_id`: {strconv.Itoa(s.cfg.LongPoll.GroupId)}}
+		lp     = &domain.LongPollServer{}
+	)
+
+	if err := s.vk.CallInto(ctx, longPollServerMethod, params, lp); err != nil {
+		return nil, errors.Wrap(err, `could not get long poll server`)
+	}
+
+	return lp, nil
+}
+
+func (s *LongPollServer) check(ctx context.Context, lp *domain.LongPollServer) (*domain.LongPollUpdates, error) {
+	var (
+		wait     = s.cfg.LongPoll.Wait
+		updates  = &domain.LongPollUpdates{}
+		endpoint string
+	)
+
+	if wait <= 0 {
+		wait = defaultWait
+	}
+
+	endpoint = fmt.Sprintf(longPollCheckTmpl, lp.Server, lp.Key, lp.Ts, wait)
+
+	return updates, s.get(ctx, endpoint, updates)
+}
+
+func (s *LongPollServer) get(ctx context.Context, endpoint string, out easyjson.Unmarshaler) error {
+	var (
+		request  *http.Request
+		response *http.Response
+		err      error
+	)
+
+	if request, err = http.NewRequestWithContext(ctx, `GET`, endpoint, nil); err != nil {
+		s.
+			logger.
+			With(zap.Error(err)).
+			Error(`build long poll request error`)
+
+		return err
+	}
+
+	if response, err = s.client.Do(request); err != nil {
+		if ctx.Err() == nil {
+			s.
+				logger.
+				With(zap.Error(err)).
+				Error(`send long poll request error`)
+		}
+
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if err = easyjson.UnmarshalFromReader(response.Body, out); err != nil {
+		s.
+			logger.
+			With(zap.Error(err)).
+			Error(`error while decoding long poll response`)
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *LongPollServer) dispatch(ctx context.Context, updates []domain.Request) {
+	var (
+		writer = newDiscardWriter()
+		err    error
+	)
+
+	for i := range updates {
+		var callback = &updates[i]
+
+		finalHandler, ok := s.messages[callback.Type]
+		if !ok {
+			s.
+				logger.
+				With(zap.String(`type`, callback.Type)).
+				Info(`there is no handler for long poll event`)
+
+			continue
+		}
+
+		if err = s.handler(ctx, finalHandler, callback, writer); err != nil {
+			s.logger.Errorf(`error while handling long poll event: %s`, err)
+		}
+	}
+}
+
+func (s *LongPollServer) sleep(ctx context.Context) {
+	var timer = time.NewTimer(retryDelay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
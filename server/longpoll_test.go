@@ -0,0 +1,83 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"github.com/sepuka/vkbotserver/api/mocks"
+	"github.com/sepuka/vkbotserver/config"
+	"github.com/sepuka/vkbotserver/domain"
+	"github.com/sepuka/vkbotserver/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type recordingHandler struct {
+	texts  []string
+	cancel context.CancelFunc
+}
+
+func (h *recordingHandler) Exec(req *domain.Request, resp http.ResponseWriter) error {
+	h.texts = append(h.texts, req.Object.Message.Text)
+	if len(h.texts) == 2 {
+		h.cancel()
+	}
+
+	return nil
+}
+
+func TestLongPollServer_Poll(t *testing.T) {
+	const (
+		serverResponse   = `{"response":{"key":"first_key","server":"https://lp.vk.com/wh1","ts":"10"}}`
+		refreshResponse  = `{"response":{"key":"second_key","server":"https://lp.vk.com/wh1","ts":"50"}}`
+		firstUpdates     = `{"ts":"11","updates":[{"type":"message_new","object":{"message":{"text":"hello","peer_id":1}},"group_id":1,"event_id":"a"}]}`
+		outdatedTs       = `{"failed":1,"ts":30}`
+		expiredKey       = `{"failed":2}`
+		secondUpdates    = `{"ts":"31","updates":[{"type":"unknown","object":{}},{"type":"message_new","object":{"message":{"text":"world","peer_id":1}},"group_id":1,"event_id":"b"}]}`
+		lpServerMethod   = `groups.getLongPollServer`
+		firstCheckQuery  = `key=first_key&ts=10&`
+		secondCheckQuery = `key=first_key&ts=11&`
+		thirdCheckQuery  = `key=first_key&ts=30&`
+		fourthCheckQuery = `key=second_key&ts=30&`
+	)
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		logger      = zap.NewNop().Sugar()
+		client      = mocks.HTTPClient{}
+		handler     = &recordingHandler{cancel: cancel}
+		cfg         = config.Config{
+			Api:      config.Api{Token: `some_token`},
+			LongPoll: config.LongPoll{GroupId: 1, Wait: 1},
+		}
+		handlerFunc = func(handler message.Executor, req *domain.Request, resp http.ResponseWriter) error {
+			return handler.Exec(req, resp)
+		}
+		server = NewLongPollServer(cfg, message.HandlerMap{`message_new`: handler}, handlerFunc, &client, logger)
+		answer = func(body string) *http.Response {
+			return &http.Response{Body: ioutil.NopCloser(bytes.NewReader([]byte(body)))}
+		}
+		urlContains = func(part string) interface{} {
+			return mock.MatchedBy(func(r *http.Request) bool {
+				return strings.Contains(r.URL.String(), part)
+			})
+		}
+	)
+
+	defer cancel()
+
+	client.On(`Do`, urlContains(lpServerMethod)).Once().Return(answer(serverResponse), nil)
+	client.On(`Do`, urlContains(firstCheckQuery)).Once().Return(answer(firstUpdates), nil)
+	client.On(`Do`, urlContains(secondCheckQuery)).Once().Return(answer(outdatedTs), nil)
+	client.On(`Do`, urlContains(thirdCheckQuery)).Once().Return(answer(expiredKey), nil)
+	client.On(`Do`, urlContains(lpServerMethod)).Once().Return(answer(refreshResponse), nil)
+	client.On(`Do`, urlContains(fourthCheckQuery)).Once().Return(answer(secondUpdates), nil)
+
+	assert.Nil(t, server.poll(ctx))
+	assert.Equal(t, []string{`hello`, `world`}, handler.texts)
+	client.AssertExpectations(t)
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"github.com/sepuka/vkbotserver/domain"
+	"github.com/sepuka/vkbotserver/errors"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	defaultWorkers   = 4
+	defaultQueueSize = 100
+)
+
+// WorkerPool handles events in background, events of the same peer are handled sequentially by one worker
+type WorkerPool struct {
+	queues  []chan *domain.Request
+	process func(*domain.Request)
+	block   bool
+	next    uint32
+	mu      sync.RWMutex
+	closed  bool
+	closing chan struct{}
+	once    sync.Once
+	wg      sync.WaitGroup
+}
+
+// NewWorkerPool creates pool and starts its workers
+func NewWorkerPool(workers int, queueSize int, block bool, process func(*domain.Request)) *WorkerPool {
+	var pool = &WorkerPool{
+		process: process,
+		block:   block,
+		closing: make(chan struct{}),
+	}
+
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+
+	pool.queues = make([]chan *domain.Request, workers)
+	for i := range pool.queues {
+		pool.queues[i] = make(chan *domain.Request, queueSize)
+		pool.wg.Add(1)
+		go pool.work(pool.queues[i])
+	}
+
+	return pool
+}
+
+// Push enqueues the event, it blocks or fails when the worker queue is full depending on the pool settings.
+// The blocked Push fails when the pool is being shut down
+func (p *WorkerPool) Push(req *domain.Request) error {
+	var queue chan *domain.Request
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return errors.NewPoolClosedError()
+	}
+
+	queue = p.queues[p.shard(req)]
+
+	if p.block {
+		select {
+		case queue <- req:
+			return nil
+		case <-p.closing:
+			return errors.NewPoolClosedError()
+		}
+	}
+
+	select {
+	case queue <- req:
+		return nil
+	default:
+		return errors.NewQueueOverflowError()
+	}
+}
+
+// Close stops accepting new events and waits until all the queued ones are handled
+func (p *WorkerPool) Close() {
+	_ = p.Shutdown(context.Background())
+}
+
+// Shutdown stops accepting new events and waits until all the queued ones are handled or ctx is done
+func (p *WorkerPool) Shutdown(ctx context.Context) error {
+	// wakes up blocked pushers, they hold the read lock
+	p.once.Do(func() {
+		close(p.closing)
+	})
+
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		for _, queue := range p.queues {
+			close(queue)
+		}
+	}
+	p.mu.Unlock()
+
+	return wait(ctx, p.wg.Wait)
+}
+
+func (p *WorkerPool) shard(req *domain.Request) int {
+	var peerId = int64(eventPeer(req))
+
+	if peerId == 0 {
+		return int(atomic.AddUint32(&p.next, 1) % uint32(len(p.queues)))
+	}
+
+	if peerId < 0 {
+		peerId = -peerId
+	}
+
+	return int(peerId % int64(len(p.queues)))
+}
+
+// eventPeer returns the peer the event belongs to, the peer of a user dialog is the user id. Zero means unknown
+func eventPeer(req *domain.Request) int {
+	switch req.Type {
+	case domain.EventMessageNew:
+		return int(req.Object.Message.PeerId)
+	case domain.EventMessageReply:
+		if msg, err := req.MessageReply(); err == nil {
+			return int(msg.PeerId)
+		}
+	case domain.EventMessageEdit:
+		if msg, err := req.MessageEdit(); err == nil {
+			return int(msg.PeerId)
+		}
+	case domain.EventMessageEvent:
+		if event, err := req.MessageEvent(); err == nil {
+			return event.PeerId
+		}
+	case domain.EventMessageAllow:
+		if event, err := req.MessageAllow(); err == nil {
+			return event.UserId
+		}
+	case domain.EventMessageDeny:
+		if event, err := req.MessageDeny(); err == nil {
+			return event.UserId
+		}
+	case domain.EventGroupJoin:
+		if event, err := req.GroupJoin(); err == nil {
+			return event.UserId
+		}
+	case domain.EventGroupLeave:
+		if event, err := req.GroupLeave(); err == nil {
+			return event.UserId
+		}
+	}
+
+	return 0
+}
+
+func (p *WorkerPool) work(queue <-chan *domain.Request) {
+	defer p.wg.Done()
+
+	for req := range queue {
+		p.process(req)
+	}
+}
@@ -0,0 +1,210 @@
+package server
+
+import (
+	"context"
+	"github.com/mailru/easyjson"
+	"github.com/sepuka/vkbotserver/config"
+	"github.com/sepuka/vkbotserver/domain"
+	"github.com/sepuka/vkbotserver/errors"
+	"github.com/sepuka/vkbotserver/message"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newPeerRequest(peerId int32, text string) *domain.Request {
+	return &domain.Request{
+		Type: `message_new`,
+		Object: domain.Object{
+			Message: domain.Message{PeerId: peerId, Text: text},
+		},
+	}
+}
+
+func TestWorkerPool_PeerOrder(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		handled = map[int32][]string{}
+		pool    = NewWorkerPool(3, 10, true, func(req *domain.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			handled[req.Object.Message.PeerId] = append(handled[req.Object.Message.PeerId], req.Object.Message.Text)
+		})
+		expected = map[int32][]string{}
+	)
+
+	for i := 0; i < 20; i++ {
+		for _, peerId := range []int32{1, 2, 3, 4} {
+			var text = strings.Repeat(`x`, i)
+			expected[peerId] = append(expected[peerId], text)
+			assert.Nil(t, pool.Push(newPeerRequest(peerId, text)))
+		}
+	}
+
+	pool.Close()
+
+	assert.Equal(t, expected, handled)
+	assert.ErrorIs(t, pool.Push(newPeerRequest(1, ``)), errors.PoolClosed)
+}
+
+func TestWorkerPool_Overflow(t *testing.T) {
+	var (
+		release = make(chan struct{})
+		started = make(chan struct{}, 1)
+		pool    = NewWorkerPool(1, 1, false, func(req *domain.Request) {
+			started <- struct{}{}
+			<-release
+		})
+	)
+
+	// the first one is taken by the worker, the second one waits in the queue
+	assert.Nil(t, pool.Push(newPeerRequest(1, `first`)))
+	<-started
+	assert.Nil(t, pool.Push(newPeerRequest(1, `second`)))
+	assert.ErrorIs(t, pool.Push(newPeerRequest(1, `third`)), errors.QueueOverflow)
+
+	close(release)
+	pool.Close()
+}
+
+func TestWorkerPool_ShutdownBlockedPush(t *testing.T) {
+	var (
+		release = make(chan struct{})
+		started = make(chan struct{}, 1)
+		pushed  = make(chan error, 1)
+		pool    = NewWorkerPool(1, 1, true, func(req *domain.Request) {
+			started <- struct{}{}
+			<-release
+		})
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	)
+
+	defer cancel()
+
+	assert.Nil(t, pool.Push(newPeerRequest(1, `first`)))
+	<-started
+	assert.Nil(t, pool.Push(newPeerRequest(1, `second`)))
+
+	go func() {
+		pushed <- pool.Push(newPeerRequest(1, `third`))
+	}()
+
+	assert.ErrorIs(t, pool.Shutdown(ctx), context.DeadlineExceeded)
+	assert.ErrorIs(t, <-pushed, errors.PoolClosed)
+
+	close(release)
+	pool.Close()
+}
+
+func TestWorkerPool_Shard(t *testing.T) {
+	var (
+		pool  = NewWorkerPool(4, 1, false, func(req *domain.Request) {})
+		tests = map[string]struct {
+			payload string
+			peerId  int
+		}{
+			`new message`: {
+				payload: `{"type": "message_new", "object": {"message": {"peer_id": 2000000001}}}`,
+				peerId:  2000000001,
+			},
+			`message reply`: {
+				payload: `{"type": "message_reply", "object": {"peer_id": 557404793, "text": "hi"}}`,
+				peerId:  557404793,
+			},
+			`callback button`: {
+				payload: `{"type": "message_event", "object": {"user_id": 557404793, "peer_id": 2000000002, "event_id": "abc"}}`,
+				peerId:  2000000002,
+			},
+			`messages allowed`: {
+				payload: `{"type": "message_allow", "object": {"user_id": 557404793, "key": "k"}}`,
+				peerId:  557404793,
+			},
+			`group join`: {
+				payload: `{"type": "group_join", "object": {"user_id": 557404793, "join_type": "join"}}`,
+				peerId:  557404793,
+			},
+			`no peer`: {
+				payload: `{"type": "like_add", "object": {"liker_id": 557404793}}`,
+			},
+		}
+	)
+
+	defer pool.Close()
+
+	for testName, testCase := range tests {
+		var req domain.Request
+
+		assert.Nil(t, easyjson.Unmarshal([]byte(testCase.payload), &req), testName)
+		assert.Equal(t, testCase.peerId, eventPeer(&req), testName)
+
+		if testCase.peerId != 0 {
+			assert.Equal(t, pool.shard(&req), pool.shard(&req), testName)
+		}
+	}
+}
+
+func TestSocketServer_ServeHTTP_Async(t *testing.T) {
+	const (
+		validConfirmationOutput = `this_is_a_valid_confirmation_output`
+
+		validConfirmationMsg = `{"type": "confirmation", "group_id": 123}`
+		newMessageMsg        = `{"type": "message_new", "group_id": 123, "object": {"message": {"peer_id": 1, "text": "hi"}}}`
+	)
+
+	var (
+		logger = zap.NewNop().Sugar()
+		cfg    = config.Config{
+			Confirmation: validConfirmationOutput,
+			Async:        config.Async{Enabled: true, Workers: 2, QueueSize: 2},
+		}
+		handled  = &countingRecorder{}
+		executor = func(handler message.Executor, req *domain.Request, resp http.ResponseWriter) error {
+			return handler.Exec(req, resp)
+		}
+		handlerMap = message.HandlerMap{
+			`confirmation`: message.NewConfirmation(cfg),
+			`message_new`:  handled,
+		}
+		server = NewSocketServer(cfg, handlerMap, executor, logger)
+		send   = func(msg string) *httptest.ResponseRecorder {
+			var resp = httptest.NewRecorder()
+
+			server.ServeHTTP(resp, &http.Request{
+				Method: "POST",
+				URL:    &url.URL{Path: "/"},
+				Header: http.Header{},
+				Body:   ioutil.NopCloser(strings.NewReader(msg)),
+			})
+
+			return resp
+		}
+		resp *httptest.ResponseRecorder
+	)
+
+	resp = send(validConfirmationMsg)
+	assert.Equal(t, validConfirmationOutput, resp.Body.String())
+
+	resp = send(newMessageMsg)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `ok`, resp.Body.String())
+
+	server.pool.Close()
+	assert.Equal(t, []string{`hi`}, handled.texts)
+}
+
+type countingRecorder struct {
+	texts []string
+}
+
+func (r *countingRecorder) Exec(req *domain.Request, resp http.ResponseWriter) error {
+	r.texts = append(r.texts, req.Object.Message.Text)
+
+	return nil
+}
@@ -1,8 +1,12 @@
 package server
 
 import (
+	"context"
+	"crypto/subtle"
+	"fmt"
 	"github.com/mailru/easyjson"
 	"github.com/pkg/errors"
+	"github.com/sepuka/vkbotserver/api"
 	"github.com/sepuka/vkbotserver/config"
 	"github.com/sepuka/vkbotserver/domain"
 	errors2 "github.com/sepuka/vkbotserver/errors"
@@ -16,6 +20,8 @@ import (
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 )
 
@@ -27,7 +33,19 @@ type SocketServer struct {
 	cfg      config.Config
 	logger   *zap.SugaredLogger
 	messages message.HandlerMap
-	handler  middleware.HandlerFunc
+	handler  middleware.ContextHandlerFunc
+	pool     *WorkerPool
+	rejected uint64
+	inflight tracker
+	mu       sync.Mutex
+	listener net.Listener
+	http     *http.Server
+	closing  int32
+	once     sync.Once
+	done     chan struct{}
+	err      error
+	ctx      context.Context
+	cancel   context.CancelFunc
 }
 
 // NewSocketServer constructor
@@ -37,18 +55,37 @@ func NewSocketServer(
 	handler middleware.HandlerFunc,
 	logger *zap.SugaredLogger,
 ) *SocketServer {
-	return &SocketServer{
+	return NewSocketServerContext(cfg, messages, middleware.WithContext(handler), logger)
+}
+
+// NewSocketServerContext creates server with the context-aware handlers chain
+func NewSocketServerContext(
+	cfg config.Config,
+	messages message.HandlerMap,
+	handler middleware.ContextHandlerFunc,
+	logger *zap.SugaredLogger,
+) *SocketServer {
+	var server = &SocketServer{
 		cfg:      cfg,
 		logger:   logger,
 		messages: messages,
 		handler:  handler,
+		done:     make(chan struct{}),
 	}
+
+	server.ctx, server.cancel = context.WithCancel(context.Background())
+
+	if cfg.Async.Enabled {
+		server.pool = NewWorkerPool(cfg.Async.Workers, cfg.Async.QueueSize, cfg.Async.Block, server.process)
+	}
+
+	return server
 }
 
-// Listen listens unix socket which created by webserver
+// Listen listens unix socket which created by webserver or tcp address depending on config
+// it returns when the server is shut down by SIGINT, SIGTERM or the Shutdown call
 func (s *SocketServer) Listen() error {
 	var (
-		socket   = s.cfg.Socket
 		signals  = make(chan os.Signal, 1)
 		stop     = make(chan error, 1)
 		listener net.Listener
@@ -56,43 +93,112 @@ func (s *SocketServer) Listen() error {
 	)
 
 	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
 
-	listener, err = net.Listen(`unix`, socket)
-	if err != nil {
-		s.logger.Errorf(`cannot listen to unix socket: %s`, err)
+	if listener, err = s.listen(); err != nil {
 		return err
 	}
 
-	if err := os.Chmod(socket, 0775); err != nil {
-		return err
-	}
-
-	defer func() error {
-		return os.Remove(socket)
-	}()
+	s.mu.Lock()
+	s.listener = listener
+	s.mu.Unlock()
 
 	go func() {
-		<-signals
-		_ = s.logger.Sync()
-		if err = listener.Close(); err != nil {
-			stop <- errors.Wrap(err, `unable to close HTTP connection`)
+		select {
+		case <-signals:
+			var ctx, cancel = context.WithTimeout(context.Background(), s.shutdownTimeout())
+			defer cancel()
+			_ = s.Shutdown(ctx)
+		case <-s.done:
 		}
 	}()
 
 	go s.server(listener, stop)
 
-	err = <-stop
+	select {
+	case err = <-stop:
+		var ctx, cancel = context.WithTimeout(context.Background(), s.shutdownTimeout())
+		defer cancel()
+		_ = s.Shutdown(ctx)
+
+		return err
+	case <-s.done:
+		return s.err
+	}
+}
+
+func (s *SocketServer) listen() (net.Listener, error) {
+	var (
+		socket   = s.cfg.Socket
+		listener net.Listener
+		err      error
+	)
+
+	switch s.network() {
+	case config.NetworkUnix:
+		if listener, err = net.Listen(`unix`, socket); err != nil {
+			s.logger.Errorf(`cannot listen to unix socket: %s`, err)
+			return nil, err
+		}
 
-	return err
+		if err = os.Chmod(socket, 0775); err != nil {
+			_ = listener.Close()
+			return nil, err
+		}
+	case config.NetworkTcp:
+		if listener, err = net.Listen(`tcp`, s.cfg.Listen.Address); err != nil {
+			s.logger.Errorf(`cannot listen to tcp address: %s`, err)
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf(`unknown listen network "%s"`, s.cfg.Listen.Network)
+	}
+
+	return listener, nil
 }
 
 func (s *SocketServer) server(listener net.Listener, c chan<- error) {
-	if err := fcgi.Serve(listener, s); err != nil {
+	var err error
+
+	switch s.protocol() {
+	case config.ProtocolFcgi:
+		err = fcgi.Serve(listener, s)
+	case config.ProtocolHttp:
+		var srv = &http.Server{Handler: s}
+		s.mu.Lock()
+		s.http = srv
+		s.mu.Unlock()
+		if s.cfg.Listen.IsTLS() {
+			err = srv.ServeTLS(listener, s.cfg.Listen.CertFile, s.cfg.Listen.KeyFile)
+		} else {
+			err = srv.Serve(listener)
+		}
+	default:
+		err = errors.Errorf(`unknown listen protocol "%s"`, s.cfg.Listen.Protocol)
+	}
+
+	if err != nil && atomic.LoadInt32(&s.closing) == 0 {
 		s.logger.Errorf(`cannot serve accept connections: %s`, err)
 		c <- err
 	}
 }
 
+func (s *SocketServer) network() string {
+	if s.cfg.Listen.Network == `` {
+		return config.NetworkUnix
+	}
+
+	return s.cfg.Listen.Network
+}
+
+func (s *SocketServer) protocol() string {
+	if s.cfg.Listen.Protocol == `` {
+		return config.ProtocolFcgi
+	}
+
+	return s.cfg.Listen.Protocol
+}
+
 func (s *SocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var (
 		callback = &domain.Request{}
@@ -100,6 +206,9 @@ func (s *SocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		err      error
 	)
 
+	s.inflight.start()
+	defer s.inflight.done()
+
 	defer r.Body.Close()
 	defer func() {
 		if err := recover(); err != nil {
@@ -132,10 +241,42 @@ func (s *SocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 			return
 		}
+	} else if err = s.verifySecret(callback); err != nil {
+		atomic.AddUint64(&s.rejected, 1)
+		s.
+			logger.
+			With(
+				zap.Error(err),
+				zap.Int32(`group_id`, callback.GroupId),
+				zap.String(`type`, callback.Type),
+				zap.String(`remote_addr`, r.RemoteAddr),
+			).
+			Warn(`event rejected`)
+		w.WriteHeader(http.StatusForbidden)
+
+		return
 	}
 
 	if finalHandler, ok := s.messages[callback.Type]; ok {
-		if err = s.handler(finalHandler, callback, w); err != nil {
+		if s.isAsync(callback) {
+			if err = s.pool.Push(callback); err != nil {
+				s.logger.Errorf(`cannot enqueue request: %s`, err)
+				w.WriteHeader(http.StatusServiceUnavailable)
+
+				return
+			}
+
+			if _, err = w.Write(api.DefaultResponseBody()); err != nil {
+				s.logger.Errorf(`cannot write answer to enqueued request: %s`, err)
+			}
+
+			return
+		}
+
+		var ctx, cancel = s.requestContext(r.Context())
+		defer cancel()
+
+		if err = s.handler(ctx, finalHandler, callback, w); err != nil {
 			s.logger.Errorf(`error while handling request: %s`, err)
 			w.WriteHeader(http.StatusInternalServerError)
 		}
@@ -144,6 +285,72 @@ func (s *SocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// requestContext is canceled when the client disconnects or the server context is canceled on shutdown
+func (s *SocketServer) requestContext(parent context.Context) (context.Context, context.CancelFunc) {
+	var ctx, cancel = context.WithCancel(parent)
+
+	go func() {
+		select {
+		case <-s.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// isAsync tells whether the event may be answered before it is handled
+// confirmation and OAuth requests need the handler output so they're always handled synchronously
+func (s *SocketServer) isAsync(callback *domain.Request) bool {
+	if s.pool == nil {
+		return false
+	}
+
+	switch callback.Type {
+	case domain.EventConfirmation, domain.OauthVkHandlerName, domain.OauthYaHandlerName:
+		return false
+	}
+
+	return true
+}
+
+// process handles the event taken from the worker pool queue
+func (s *SocketServer) process(callback *domain.Request) {
+	defer func() {
+		if err := recover(); err != nil {
+			s.logger.Errorf(`panic while handling enqueued request: %s`, err)
+		}
+	}()
+
+	if err := s.handler(s.ctx, s.messages[callback.Type], callback, newDiscardWriter()); err != nil {
+		s.logger.Errorf(`error while handling enqueued request: %s`, err)
+	}
+}
+
+// RejectedEvents returns the number of events rejected because of an invalid secret key
+func (s *SocketServer) RejectedEvents() uint64 {
+	return atomic.LoadUint64(&s.rejected)
+}
+
+func (s *SocketServer) verifySecret(callback *domain.Request) error {
+	var secret, ok = s.cfg.Secrets[callback.GroupId]
+
+	if len(s.cfg.Secrets) == 0 {
+		return nil
+	}
+
+	if !ok {
+		return errors2.NewInvalidSecretError(fmt.Sprintf(`no secret key for group %d`, callback.GroupId))
+	}
+
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(callback.Secret)) != 1 {
+		return errors2.NewInvalidSecretError(fmt.Sprintf(`invalid secret key for group %d`, callback.GroupId))
+	}
+
+	return nil
+}
+
 func (s *SocketServer) buildOAuthCallback(r *http.Request) (*domain.Request, error) {
 	var (
 		path = strings.TrimPrefix(r.URL.Path, s.cfg.PathPrefix)
@@ -2,6 +2,7 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"github.com/sepuka/vkbotserver/api"
@@ -11,13 +12,18 @@ import (
 	mocks2 "github.com/sepuka/vkbotserver/domain/mocks"
 	"github.com/sepuka/vkbotserver/message"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 type mistakenHandler struct{}
@@ -161,7 +167,9 @@ func TestSocketServer_ServeHTTP_VkOauth(t *testing.T) {
 		Body: ioutil.NopCloser(bytes.NewReader([]byte(tokenResponse))),
 	}
 	vkTokenRequest, _ = http.NewRequest(`GET`, `https://oauth.vk.com/access_token?client_id=client_id&client_secret=client_secret&redirect_uri=https://host.domain/path?args&code=777`, nil)
-	client.On(`Do`, vkTokenRequest).Return(vkTokenResponse, nil)
+	client.On(`Do`, mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == vkTokenRequest.URL.String()
+	})).Return(vkTokenResponse, nil)
 
 	user = &domain.User{Token: cookie, LastName: `some last name`, FirstName: `some first name`}
 	userRepo.On(`GetByExternalId`, domain.OAuthVk, `66748`).Return(user, nil)
@@ -184,3 +192,256 @@ func TestSocketServer_ServeHTTP_VkOauth(t *testing.T) {
 
 	assert.Equal(t, http.StatusFound, resp.Code, errMsg)
 }
+
+func TestSocketServer_ListenTcpHttp(t *testing.T) {
+	const (
+		validConfirmationOutput = `this_is_a_valid_confirmation_output`
+		validConfirmationMsg    = `{"type": "confirmation", "group_id": 123}`
+	)
+
+	var (
+		logger = zap.NewNop().Sugar()
+		cfg    = config.Config{
+			Confirmation: validConfirmationOutput,
+			Listen: config.Listen{
+				Network:  config.NetworkTcp,
+				Protocol: config.ProtocolHttp,
+				Address:  `127.0.0.1:0`,
+			},
+		}
+		handler = func(handler message.Executor, req *domain.Request, resp http.ResponseWriter) error {
+			return handler.Exec(req, resp)
+		}
+		handlerMap = message.HandlerMap{
+			`confirmation`: message.NewConfirmation(cfg),
+		}
+		server = NewSocketServer(cfg, handlerMap, handler, logger)
+		stop   = make(chan error, 1)
+	)
+
+	listener, err := server.listen()
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	go server.server(listener, stop)
+
+	resp, err := http.Post(`http://`+listener.Addr().String()+`/`, `application/json`, strings.NewReader(validConfirmationMsg))
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, validConfirmationOutput, string(body))
+}
+
+func TestSocketServer_ListenUnknownNetwork(t *testing.T) {
+	var (
+		cfg = config.Config{
+			Listen: config.Listen{Network: `udp`},
+		}
+		server = NewSocketServer(cfg, message.HandlerMap{}, nil, zap.NewNop().Sugar())
+	)
+
+	_, err := server.listen()
+	assert.NotNil(t, err)
+}
+
+func TestSocketServer_ServeHTTP_Secret(t *testing.T) {
+	const (
+		validConfirmationOutput = `this_is_a_valid_confirmation_output`
+
+		validSecretMsg   = `{"type": "confirmation", "group_id": 123, "secret": "top_secret"}`
+		invalidSecretMsg = `{"type": "confirmation", "group_id": 123, "secret": "top_secre"}`
+		emptySecretMsg   = `{"type": "confirmation", "group_id": 123}`
+		otherGroupMsg    = `{"type": "confirmation", "group_id": 321}`
+	)
+
+	var (
+		errMsg string
+		req    *http.Request
+		resp   *httptest.ResponseRecorder
+		logger = zap.NewNop().Sugar()
+		cfg    = config.Config{
+			Confirmation: validConfirmationOutput,
+			Secrets:      map[int32]string{123: `top_secret`},
+		}
+		handler = func(handler message.Executor, req *domain.Request, resp http.ResponseWriter) error {
+			return handler.Exec(req, resp)
+		}
+		handlerMap = message.HandlerMap{
+			`confirmation`: message.NewConfirmation(cfg),
+		}
+		server = NewSocketServer(cfg, handlerMap, handler, logger)
+
+		tests = map[string]struct {
+			incomingMsg  string
+			expectedCode int
+		}{
+			`valid secret`: {
+				incomingMsg:  validSecretMsg,
+				expectedCode: http.StatusOK,
+			},
+			`invalid secret`: {
+				incomingMsg:  invalidSecretMsg,
+				expectedCode: http.StatusForbidden,
+			},
+			`empty secret`: {
+				incomingMsg:  emptySecretMsg,
+				expectedCode: http.StatusForbidden,
+			},
+			`group without secret`: {
+				incomingMsg:  otherGroupMsg,
+				expectedCode: http.StatusForbidden,
+			},
+		}
+	)
+
+	for testName, testCase := range tests {
+		errMsg = fmt.Sprintf(`there is an unexpected error "%s"`, testName)
+
+		resp = httptest.NewRecorder()
+		req = &http.Request{
+			Method: "POST",
+			Host:   "vk.com",
+			URL:    &url.URL{Path: "/"},
+			Header: http.Header{},
+			Body:   ioutil.NopCloser(strings.NewReader(testCase.incomingMsg)),
+		}
+
+		server.ServeHTTP(resp, req)
+
+		assert.Equal(t, testCase.expectedCode, resp.Code, errMsg)
+	}
+
+	assert.Equal(t, uint64(3), server.RejectedEvents())
+}
+
+type slowHandler struct {
+	started  chan struct{}
+	release  chan struct{}
+	finished bool
+}
+
+func (h *slowHandler) Exec(req *domain.Request, resp http.ResponseWriter) error {
+	close(h.started)
+	<-h.release
+	h.finished = true
+	_, err := resp.Write(api.DefaultResponseBody())
+
+	return err
+}
+
+func TestSocketServer_Shutdown(t *testing.T) {
+	const (
+		newMessageMsg = `{"type": "message_new", "group_id": 123}`
+	)
+
+	var (
+		socket = filepath.Join(t.TempDir(), `server.sock`)
+		logger = zap.NewNop().Sugar()
+		cfg    = config.Config{
+			Socket: socket,
+			Listen: config.Listen{Protocol: config.ProtocolHttp},
+		}
+		handler = func(handler message.Executor, req *domain.Request, resp http.ResponseWriter) error {
+			return handler.Exec(req, resp)
+		}
+		slow   = &slowHandler{started: make(chan struct{}), release: make(chan struct{})}
+		server = NewSocketServer(cfg, message.HandlerMap{`message_new`: slow}, handler, logger)
+		client = &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return (&net.Dialer{}).DialContext(ctx, `unix`, socket)
+				},
+			},
+		}
+		listened  = make(chan error, 1)
+		responded = make(chan int, 1)
+		shutdown  = make(chan error, 1)
+	)
+
+	go func() {
+		listened <- server.Listen()
+	}()
+
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(socket)
+		return err == nil
+	}, time.Second, time.Millisecond)
+
+	go func() {
+		resp, err := client.Post(`http://unix/`, `application/json`, strings.NewReader(newMessageMsg))
+		if err != nil {
+			responded <- 0
+			return
+		}
+		resp.Body.Close()
+		responded <- resp.StatusCode
+	}()
+
+	<-slow.started
+
+	go func() {
+		shutdown <- server.Shutdown(context.Background())
+	}()
+
+	// the running handler holds the shutdown
+	select {
+	case <-shutdown:
+		t.Fatal(`shutdown must wait for the running handler`)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(slow.release)
+
+	assert.Nil(t, <-shutdown)
+	assert.Nil(t, <-listened)
+	assert.Equal(t, http.StatusOK, <-responded)
+	assert.True(t, slow.finished)
+
+	_, err := os.Stat(socket)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestSocketServer_ShutdownTimeout(t *testing.T) {
+	var (
+		server      = NewSocketServer(config.Config{}, message.HandlerMap{}, nil, zap.NewNop().Sugar())
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	)
+
+	defer cancel()
+
+	server.inflight.start()
+	assert.ErrorIs(t, server.Shutdown(ctx), context.DeadlineExceeded)
+	server.inflight.done()
+}
+
+func TestSocketServer_ShutdownCancelsHandlers(t *testing.T) {
+	var (
+		started = make(chan struct{})
+		handler = func(ctx context.Context, handler message.Executor, req *domain.Request, resp http.ResponseWriter) error {
+			close(started)
+			<-ctx.Done()
+
+			return ctx.Err()
+		}
+		server      = NewSocketServerContext(config.Config{}, message.HandlerMap{`message_new`: mistakenHandler{}}, handler, zap.NewNop().Sugar())
+		resp        = httptest.NewRecorder()
+		req         = httptest.NewRequest(http.MethodPost, `/`, strings.NewReader(`{"type": "message_new", "group_id": 123}`))
+		served      = make(chan struct{})
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	)
+
+	defer cancel()
+
+	go func() {
+		server.ServeHTTP(resp, req)
+		close(served)
+	}()
+
+	<-started
+
+	assert.ErrorIs(t, server.Shutdown(ctx), context.DeadlineExceeded)
+	<-served
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/sepuka/vkbotserver/config"
+	"github.com/sepuka/vkbotserver/message"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultShutdownTimeout = 10 * time.Second
+)
+
+// tracker counts running requests, unlike sync.WaitGroup it allows to start new ones while somebody is waiting
+type tracker struct {
+	mu    sync.Mutex
+	count int
+	idle  chan struct{}
+}
+
+// Shutdown stops accepting new requests and waits for running handlers, queued events and handlers background work
+// such as OAuth callbacks. Then it flushes the logger and removes the socket file.
+// The ctx deadline limits waiting, the socket is removed anyway and the context of still running handlers is canceled.
+func (s *SocketServer) Shutdown(ctx context.Context) error {
+	s.once.Do(func() {
+		s.err = s.shutdown(ctx)
+		s.cancel()
+		close(s.done)
+	})
+
+	<-s.done
+
+	return s.err
+}
+
+func (s *SocketServer) shutdown(ctx context.Context) error {
+	var (
+		listener net.Listener
+		srv      *http.Server
+		err      error
+		result   error
+	)
+
+	atomic.StoreInt32(&s.closing, 1)
+
+	s.mu.Lock()
+	listener, srv = s.listener, s.http
+	s.mu.Unlock()
+
+	if srv != nil {
+		if err = srv.Shutdown(ctx); err != nil {
+			result = errors.Wrap(err, `unable to shutdown HTTP server`)
+		}
+	} else if listener != nil {
+		if err = listener.Close(); err != nil {
+			result = errors.Wrap(err, `unable to close HTTP connection`)
+		}
+	}
+
+	if err = s.inflight.wait(ctx); err != nil && result == nil {
+		result = errors.Wrap(err, `running handlers were not finished`)
+	}
+
+	if s.pool != nil {
+		if err = s.pool.Shutdown(ctx); err != nil && result == nil {
+			result = errors.Wrap(err, `queued events were not handled`)
+		}
+	}
+
+	for _, executor := range s.messages {
+		if waiter, ok := executor.(message.Waiter); ok {
+			if err = wait(ctx, waiter.Wait); err != nil && result == nil {
+				result = errors.Wrap(err, `background work was not finished`)
+			}
+		}
+	}
+
+	_ = s.logger.Sync()
+
+	if listener != nil && s.network() == config.NetworkUnix {
+		if err = os.Remove(s.cfg.Socket); err != nil && !os.IsNotExist(err) && result == nil {
+			result = errors.Wrap(err, `unable to remove socket`)
+		}
+	}
+
+	return result
+}
+
+func (s *SocketServer) shutdownTimeout() time.Duration {
+	if s.cfg.ShutdownTimeout <= 0 {
+		return defaultShutdownTimeout
+	}
+
+	return s.cfg.ShutdownTimeout
+}
+
+// wait calls the blocking fn and gives up when ctx is done
+func wait(ctx context.Context, fn func()) error {
+	var done = make(chan struct{})
+
+	go func() {
+		fn()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *tracker) start() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.count == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.count++
+}
+
+func (t *tracker) done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.count--
+	if t.count == 0 {
+		close(t.idle)
+	}
+}
+
+func (t *tracker) wait(ctx context.Context) error {
+	var idle chan struct{}
+
+	t.mu.Lock()
+	if t.count == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	idle = t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
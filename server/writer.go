@@ -0,0 +1,25 @@
+package server
+
+import "net/http"
+
+// discardWriter is a response writer for events which do not need an answer
+type discardWriter struct {
+	header http.Header
+}
+
+func newDiscardWriter() *discardWriter {
+	return &discardWriter{
+		header: http.Header{},
+	}
+}
+
+func (w *discardWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardWriter) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+func (w *discardWriter) WriteHeader(int) {
+}